@@ -0,0 +1,816 @@
+package semver
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// bound defines one of the limits of an interval. A nil version means the
+// interval is not bounded on that side
+type bound struct {
+	v         *Version
+	inclusive bool
+}
+
+// interval defines a contiguous set of versions
+type interval struct {
+	min bound
+	max bound
+}
+
+// versionSet is a sorted list of disjoint intervals, ordered by SemVer precedence. Sets describe
+// exactly the release versions matched by an expression, but they do not apply the npm pre-release
+// rules of Range.Contains, and x-ranges are bounded by release versions ("1.x" is ">=1.0.0 <2.0.0")
+type versionSet []interval
+
+// lowestVersion returns the lowest possible version, used as the lower bound of unbounded intervals
+func lowestVersion() *Version {
+	return NewVersion(0, 0, 0)
+}
+
+func fullInterval() interval {
+	return interval{min: bound{v: lowestVersion(), inclusive: true}}
+}
+
+func compareVersions(v1, v2 *Version) int {
+	return v1.compare(v2)
+}
+
+// lowest returns the lowest plain version matching the x-range
+func (v *GlobVersion) lowest() *Version {
+	switch {
+	case v.anyMajor:
+		return NewVersion(0, 0, 0)
+	case v.anyMinor:
+		return NewVersion(v.Major, 0, 0)
+	case v.anyPatch:
+		return NewVersion(v.Major, v.Minor, 0)
+	default:
+		return NewVersion(v.Major, v.Minor, v.Patch, v.PreRelease)
+	}
+}
+
+// versionSet returns the set of versions contained in the range
+func (r *Range) versionSet() versionSet {
+	i := fullInterval()
+	if v := r.MinVersion; v != nil {
+		switch {
+		case r.AllowMinEquality:
+			i.min = bound{v: v.lowest(), inclusive: true}
+		case v.anyMajor:
+			// Nothing is greater than every version
+			return versionSet{}
+		case v.anyMinor:
+			i.min = bound{v: NewVersion(v.Major+1, 0, 0), inclusive: true}
+		case v.anyPatch:
+			i.min = bound{v: NewVersion(v.Major, v.Minor+1, 0), inclusive: true}
+		default:
+			i.min = bound{v: v.lowest(), inclusive: false}
+		}
+	}
+	if v := r.MaxVersion; v != nil {
+		switch {
+		case !r.AllowMaxEquality:
+			if v.anyMajor {
+				// Nothing is less than every version
+				return versionSet{}
+			}
+			i.max = bound{v: v.lowest(), inclusive: false}
+		case v.anyMajor:
+			// Any version is less or equal than *
+		case v.anyMinor:
+			i.max = bound{v: NewVersion(v.Major+1, 0, 0), inclusive: false}
+		case v.anyPatch:
+			i.max = bound{v: NewVersion(v.Major, v.Minor+1, 0), inclusive: false}
+		default:
+			i.max = bound{v: v.lowest(), inclusive: true}
+		}
+	}
+	return versionSet{i}.normalize()
+}
+
+func (i interval) isEmpty() bool {
+	if i.max.v == nil {
+		return false
+	}
+	switch c := compareVersions(i.min.v, i.max.v); {
+	case c > 0:
+		return true
+	case c == 0:
+		return !i.min.inclusive || !i.max.inclusive
+	default:
+		return false
+	}
+}
+
+func (i interval) contains(v *Version) bool {
+	return compareLower(bound{v: v, inclusive: true}, i.min) >= 0 && compareUpper(bound{v: v, inclusive: true}, i.max) <= 0
+}
+
+func (s versionSet) contains(v *Version) bool {
+	for _, i := range s {
+		if i.contains(v) {
+			return true
+		}
+	}
+	return false
+}
+
+func (i interval) isPoint() bool {
+	return i.max.v != nil && i.min.inclusive && i.max.inclusive && compareVersions(i.min.v, i.max.v) == 0
+}
+
+// isUnboundedBelow returns true if the interval starts at the lowest possible version
+func (i interval) isUnboundedBelow() bool {
+	return i.min.inclusive && compareVersions(i.min.v, lowestVersion()) == 0
+}
+
+// compareLower compares two lower bounds. An inclusive bound starts before an exclusive one
+func compareLower(b1, b2 bound) int {
+	if c := compareVersions(b1.v, b2.v); c != 0 {
+		return c
+	}
+	switch {
+	case b1.inclusive == b2.inclusive:
+		return 0
+	case b1.inclusive:
+		return -1
+	default:
+		return 1
+	}
+}
+
+// compareUpper compares two upper bounds. A nil version is greater than any other,
+// and an exclusive bound ends before an inclusive one
+func compareUpper(b1, b2 bound) int {
+	switch {
+	case b1.v == nil && b2.v == nil:
+		return 0
+	case b1.v == nil:
+		return 1
+	case b2.v == nil:
+		return -1
+	}
+	if c := compareVersions(b1.v, b2.v); c != 0 {
+		return c
+	}
+	switch {
+	case b1.inclusive == b2.inclusive:
+		return 0
+	case b1.inclusive:
+		return 1
+	default:
+		return -1
+	}
+}
+
+// touches returns true if the interval i2, starting after i, overlaps or is adjacent to i
+func (i interval) touches(i2 interval) bool {
+	if i.max.v == nil {
+		return true
+	}
+	switch c := compareVersions(i.max.v, i2.min.v); {
+	case c > 0:
+		return true
+	case c == 0:
+		return i.max.inclusive || i2.min.inclusive
+	default:
+		return false
+	}
+}
+
+func (s versionSet) Len() int           { return len(s) }
+func (s versionSet) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+func (s versionSet) Less(i, j int) bool { return compareLower(s[i].min, s[j].min) < 0 }
+
+// normalize returns the set as a sorted list of disjoint, non-empty intervals
+func (s versionSet) normalize() versionSet {
+	list := versionSet{}
+	for _, i := range s {
+		if !i.isEmpty() {
+			list = append(list, i)
+		}
+	}
+	sort.Stable(list)
+	result := versionSet{}
+	for _, i := range list {
+		if last := len(result) - 1; last >= 0 && result[last].touches(i) {
+			if compareUpper(i.max, result[last].max) > 0 {
+				result[last].max = i.max
+			}
+			continue
+		}
+		result = append(result, i)
+	}
+	return result
+}
+
+func (s versionSet) union(s2 versionSet) versionSet {
+	list := append(versionSet{}, s...)
+	return append(list, s2...).normalize()
+}
+
+func (s versionSet) intersect(s2 versionSet) versionSet {
+	result := versionSet{}
+	for _, i1 := range s {
+		for _, i2 := range s2 {
+			i := i1
+			if compareLower(i2.min, i.min) > 0 {
+				i.min = i2.min
+			}
+			if compareUpper(i2.max, i.max) < 0 {
+				i.max = i2.max
+			}
+			result = append(result, i)
+		}
+	}
+	return result.normalize()
+}
+
+func (s versionSet) complement() versionSet {
+	return s.complementFrom(fullInterval().min)
+}
+
+// complementFrom returns the versions not contained in s, starting at floor
+func (s versionSet) complementFrom(floor bound) versionSet {
+	result := versionSet{}
+	next := floor
+	for _, i := range s.normalize() {
+		result = append(result, interval{min: next, max: bound{v: i.min.v, inclusive: !i.min.inclusive}})
+		if i.max.v == nil {
+			return result.normalize()
+		}
+		next = bound{v: i.max.v, inclusive: !i.max.inclusive}
+	}
+	return append(result, interval{min: next}).normalize()
+}
+
+// comparator is a range written as an operator followed by a version, such as ">=1.2.0"
+type comparator struct {
+	operator string
+	v        *GlobVersion
+}
+
+func (c comparator) String() string {
+	if c.v.anyMajor {
+		return "*"
+	}
+	return c.operator + c.v.String()
+}
+
+// comparators returns the comparators describing the interval, joined by AND. An interval
+// starting at floor, the lowest version of its set, is not bounded below
+func (i interval) comparators(floor *Version) []comparator {
+	unboundedBelow := i.min.inclusive && compareVersions(i.min.v, floor) == 0
+	switch {
+	case i.isPoint():
+		return []comparator{{v: &GlobVersion{Version: i.min.v}}}
+	case unboundedBelow && i.max.v == nil:
+		return []comparator{{v: MustParseGlobVersion("*")}}
+	}
+	list := []comparator{}
+	if !unboundedBelow {
+		op := ">"
+		if i.min.inclusive {
+			op = ">="
+		}
+		list = append(list, comparator{operator: op, v: &GlobVersion{Version: i.min.v}})
+	}
+	if i.max.v != nil {
+		op := "<"
+		if i.max.inclusive {
+			op = "<="
+		}
+		list = append(list, comparator{operator: op, v: &GlobVersion{Version: i.max.v}})
+	}
+	return list
+}
+
+func (i interval) String() string {
+	list := []string{}
+	for _, c := range i.comparators(lowestVersion()) {
+		list = append(list, c.String())
+	}
+	return strings.Join(list, " ")
+}
+
+// groups returns the comparators describing the set, as an union of intersections. floor is the
+// lowest version of the set: an empty set is written as the versions below it
+func (s versionSet) groups(floor *Version) [][]comparator {
+	if len(s) == 0 {
+		return [][]comparator{{{operator: "<", v: &GlobVersion{Version: floor}}}}
+	}
+	groups := [][]comparator{}
+	for _, i := range s {
+		groups = append(groups, i.comparators(floor))
+	}
+	return groups
+}
+
+// format returns the set written as an expression, taking floor as its lowest version
+func (s versionSet) format(floor *Version) string {
+	list := []string{}
+	for _, group := range s.groups(floor) {
+		and := []string{}
+		for _, c := range group {
+			and = append(and, c.String())
+		}
+		list = append(list, strings.Join(and, " "))
+	}
+	return strings.Join(list, " || ")
+}
+
+func (s versionSet) String() string {
+	return s.format(lowestVersion())
+}
+
+// toRange returns a Range containing the same versions as the interval
+func (i interval) toRange() *Range {
+	r := &Range{AllowMinEquality: i.min.inclusive, AllowMaxEquality: i.max.inclusive}
+	if !i.isUnboundedBelow() {
+		r.MinVersion = &GlobVersion{Version: i.min.v}
+	}
+	if i.max.v != nil {
+		r.MaxVersion = &GlobVersion{Version: i.max.v}
+	}
+	return r
+}
+
+// expression returns the set as an union of ranges, parsed with the configuration c. Its syntax
+// tree is the one ParseExpr builds from the same text. With IncludePrerelease, the set must
+// contain exactly the versions to match, pre-releases included
+func (s versionSet) expression(c parseConfig) *Expr {
+	str := ""
+	or := &OrNode{}
+	for _, group := range s.groups(c.floor()) {
+		if str != "" {
+			str += " || "
+		}
+		and := &AndNode{Location: Span{Start: len(str)}}
+		for j, cmp := range group {
+			if j > 0 {
+				str += " "
+			}
+			r, _ := newRange(cmp.operator, cmp.v, nil, c)
+			start := len(str)
+			str += cmp.String()
+			and.Operands = append(and.Operands, &RangeNode{Range: r, Operator: cmp.operator, Location: Span{Start: start, End: len(str)}})
+		}
+		and.Location.End = len(str)
+		if len(and.Operands) == 1 {
+			or.Operands = append(or.Operands, and.Operands[0])
+		} else {
+			or.Operands = append(or.Operands, and)
+		}
+	}
+	or.Location = Span{Start: 0, End: len(str)}
+	var root Node = or
+	if len(or.Operands) == 1 {
+		root = or.Operands[0]
+	}
+	return &Expr{str: str, root: root, parseConfig: c}
+}
+
+// setOf returns the set of versions accepted by e. Only expressions returned by ParseExpr and ranges
+// describe intervals of SemVer versions; other implementations of Expression are not supported
+func setOf(e Expression) (versionSet, error) {
+	switch x := e.(type) {
+	case *Expr:
+		return nodeSet(x.AST()), nil
+	case *Range:
+		return x.versionSet(), nil
+	default:
+		return nil, fmt.Errorf("unsupported expression type: %T", e)
+	}
+}
+
+// nodeSet returns the set of versions accepted by the node n
+func nodeSet(n Node) versionSet {
+	switch x := n.(type) {
+	case *RangeNode:
+		return x.Range.versionSet()
+	case *BranchNode:
+		return versionSet{}
+	case *NotNode:
+		return nodeSet(x.Operand).complement()
+	case *AndNode:
+		s := versionSet{fullInterval()}
+		for _, o := range x.Operands {
+			s = s.intersect(nodeSet(o))
+		}
+		return s
+	case *OrNode:
+		s := versionSet{}
+		for _, o := range x.Operands {
+			s = s.union(nodeSet(o))
+		}
+		return s
+	default:
+		panic(fmt.Errorf(`Unsupported element type %T`, n))
+	}
+}
+
+// includedSetOf returns the set of versions matched by e, pre-releases included, when e was
+// parsed with IncludePrerelease
+func includedSetOf(e Expression) versionSet {
+	switch x := e.(type) {
+	case *Expr:
+		return exactSetOf(x.AST(), true).releases
+	case *Range:
+		return x.exactVersionSet()
+	default:
+		panic(fmt.Errorf("unsupported expression type: %T", e))
+	}
+}
+
+// setsOf returns the sets of versions accepted by the expressions, and the configuration to parse
+// the expressions built from them with. When all of them were parsed with IncludePrerelease, the
+// sets hold exactly the versions they match, and the configuration keeps the option. Otherwise,
+// sets are the ones returned by setOf
+func setsOf(exprs ...Expression) ([]versionSet, parseConfig, error) {
+	c := parseConfig{includePrerelease: true}
+	for _, e := range exprs {
+		switch x := e.(type) {
+		case *Expr:
+			c.includePrerelease = c.includePrerelease && x.includePrerelease
+		case *Range:
+			c.includePrerelease = c.includePrerelease && x.IncludePrerelease
+		default:
+			return nil, c, fmt.Errorf("unsupported expression type: %T", e)
+		}
+	}
+	sets := []versionSet{}
+	for _, e := range exprs {
+		s, _ := setOf(e)
+		if c.includePrerelease {
+			s = includedSetOf(e)
+		}
+		sets = append(sets, s)
+	}
+	return sets, c, nil
+}
+
+// floor returns the lowest version matched by expressions parsed with the configuration
+func (c parseConfig) floor() *Version {
+	if c.includePrerelease {
+		return lowestPreRelease()
+	}
+	return lowestVersion()
+}
+
+// complementIn returns the versions not contained in s, for expressions parsed with c
+func (s versionSet) complementIn(c parseConfig) versionSet {
+	return s.complementFrom(bound{v: c.floor(), inclusive: true})
+}
+
+// Intersect returns a normalized expression matching the versions accepted by both e1 and e2.
+// As the rest of set operations, it supports expressions returned by ParseExpr and ranges, and
+// fails with any other type. The result keeps IncludePrerelease if both e1 and e2 were parsed with it
+func Intersect(e1 Expression, e2 Expression) (Expression, error) {
+	sets, c, err := setsOf(e1, e2)
+	if err != nil {
+		return nil, err
+	}
+	return sets[0].intersect(sets[1]).expression(c), nil
+}
+
+// Union returns a normalized expression matching the versions accepted by e1 or e2
+func Union(e1 Expression, e2 Expression) (Expression, error) {
+	sets, c, err := setsOf(e1, e2)
+	if err != nil {
+		return nil, err
+	}
+	return sets[0].union(sets[1]).expression(c), nil
+}
+
+// Complement returns a normalized expression matching the versions not accepted by e
+func Complement(e Expression) (Expression, error) {
+	sets, c, err := setsOf(e)
+	if err != nil {
+		return nil, err
+	}
+	return sets[0].complementIn(c).expression(c), nil
+}
+
+// IsEmpty returns true if no version, pre-releases included, can satisfy the expression e
+func IsEmpty(e Expression) (bool, error) {
+	sets, err := exactSetsOf(e)
+	if err != nil {
+		return false, err
+	}
+	return sets[0].isEmpty(), nil
+}
+
+// Intersect returns a normalized expression matching the versions contained in both r and r2
+func (r *Range) Intersect(r2 *Range) Expression {
+	sets, c, _ := setsOf(r, r2)
+	return sets[0].intersect(sets[1]).expression(c)
+}
+
+// Union returns a normalized expression matching the versions contained in r or r2
+func (r *Range) Union(r2 *Range) Expression {
+	sets, c, _ := setsOf(r, r2)
+	return sets[0].union(sets[1]).expression(c)
+}
+
+// Complement returns a normalized expression matching the versions not contained in r
+func (r *Range) Complement() Expression {
+	sets, c, _ := setsOf(r)
+	return sets[0].complementIn(c).expression(c)
+}
+
+// IsEmpty returns true if no version is contained in the range
+func (r *Range) IsEmpty() bool {
+	sets, _ := exactSetsOf(r)
+	return sets[0].isEmpty()
+}
+
+// IsSubset returns true if every version accepted by e1 is also accepted by e2, pre-releases included
+func IsSubset(e1 Expression, e2 Expression) (bool, error) {
+	sets, err := exactSetsOf(e1, e2)
+	if err != nil {
+		return false, err
+	}
+	return sets[0].isSubset(sets[1]), nil
+}
+
+// Intersects returns true if at least one version, pre-releases included, is accepted by both e1 and e2
+func Intersects(e1 Expression, e2 Expression) (bool, error) {
+	sets, err := exactSetsOf(e1, e2)
+	if err != nil {
+		return false, err
+	}
+	return !sets[0].intersect(sets[1]).isEmpty(), nil
+}
+
+// Simplify returns an expression accepting the same versions as e, written as the
+// minimal union of disjoint intervals. Overlapping ranges are merged and redundant terms dropped.
+// The result keeps IncludePrerelease; without it, the pre-releases matched by e are written as
+// separate intervals, limited by pre-releases of the same major, minor and patch
+func Simplify(e Expression) (Expression, error) {
+	sets, c, err := setsOf(e)
+	if err != nil {
+		return nil, err
+	}
+	if c.includePrerelease {
+		return sets[0].expression(c), nil
+	}
+	exact, _ := exactSetsOf(e)
+	return exact[0].expression(), nil
+}
+
+// Equivalent returns true if e1 and e2 accept exactly the same versions, pre-releases included
+func Equivalent(e1 Expression, e2 Expression) (bool, error) {
+	sets, err := exactSetsOf(e1, e2)
+	if err != nil {
+		return false, err
+	}
+	return sets[0].isSubset(sets[1]) && sets[1].isSubset(sets[0]), nil
+}
+
+// lowestPreRelease is the lowest possible version, taking pre-releases into account
+func lowestPreRelease() *Version {
+	return NewVersion(0, 0, 0, "0")
+}
+
+// preReleaseFloor returns the lowest pre-release of the version with the provided major, minor and patch
+func preReleaseFloor(major, minor, patch int64) *Version {
+	return NewVersion(major, minor, patch, "0")
+}
+
+// splitSet describes a set of versions as two sets: the release versions and the pre-release
+// versions it contains. Unlike versionSet, it allows describing the npm pre-release rules
+type splitSet struct {
+	releases    versionSet
+	preReleases versionSet
+}
+
+func fullSplitSet() splitSet {
+	full := versionSet{{min: bound{v: lowestPreRelease(), inclusive: true}}}
+	return splitSet{releases: full, preReleases: full}
+}
+
+func (s splitSet) union(s2 splitSet) splitSet {
+	return splitSet{releases: s.releases.union(s2.releases), preReleases: s.preReleases.union(s2.preReleases)}
+}
+
+func (s splitSet) intersect(s2 splitSet) splitSet {
+	return splitSet{releases: s.releases.intersect(s2.releases), preReleases: s.preReleases.intersect(s2.preReleases)}
+}
+
+func (s splitSet) complement() splitSet {
+	floor := bound{v: lowestPreRelease(), inclusive: true}
+	return splitSet{releases: s.releases.complementFrom(floor), preReleases: s.preReleases.complementFrom(floor)}
+}
+
+// hasRelease returns true if the interval contains a release version
+func (i interval) hasRelease() bool {
+	v := i.min.v
+	switch {
+	case v.PreRelease != "":
+		v = NewVersion(v.Major, v.Minor, v.Patch)
+	case !i.min.inclusive:
+		v = NewVersion(v.Major, v.Minor, v.Patch+1)
+	}
+	return i.contains(v)
+}
+
+// hasPreRelease returns true if the interval contains a pre-release version
+func (i interval) hasPreRelease() bool {
+	v := i.min.v
+	switch {
+	case v.PreRelease == "":
+		v = preReleaseFloor(v.Major, v.Minor, v.Patch+1)
+	case !i.min.inclusive:
+		// No pre-release sorts between "x" and "x.0"
+		v = NewVersion(v.Major, v.Minor, v.Patch, v.PreRelease+".0")
+	}
+	return i.contains(v)
+}
+
+// isEmpty returns true if the set contains no version
+func (s splitSet) isEmpty() bool {
+	for _, i := range s.releases {
+		if i.hasRelease() {
+			return false
+		}
+	}
+	for _, i := range s.preReleases {
+		if i.hasPreRelease() {
+			return false
+		}
+	}
+	return true
+}
+
+// isSubset returns true if every version of s is contained in s2
+func (s splitSet) isSubset(s2 splitSet) bool {
+	return s.intersect(s2.complement()).isEmpty()
+}
+
+// releaseBounds returns an interval limited by release versions, containing the same releases as i
+func (i interval) releaseBounds() interval {
+	if v := i.min.v; v.PreRelease != "" {
+		i.min = bound{v: NewVersion(v.Major, v.Minor, v.Patch), inclusive: true}
+	}
+	if v := i.max.v; v != nil && v.PreRelease != "" {
+		i.max = bound{v: NewVersion(v.Major, v.Minor, v.Patch), inclusive: false}
+	}
+	return i
+}
+
+// expression returns an expression matching exactly the versions of the set, parsed without
+// IncludePrerelease. Its pre-releases must share the major, minor and patch of a pre-release
+// limit, as in the sets returned by exactSetOf without preReleaseAllowed. They are written as
+// separate intervals, limited by those pre-releases
+func (s splitSet) expression() *Expr {
+	list := versionSet{}
+	for _, i := range s.releases {
+		if i = i.releaseBounds(); !i.isEmpty() {
+			list = append(list, i)
+		}
+	}
+	list = append(list.normalize(), s.preReleases...)
+	sort.Sort(list)
+	return list.expression(parseConfig{})
+}
+
+// exactSetsOf returns the versions, including pre-releases, matched by each of the expressions
+func exactSetsOf(exprs ...Expression) ([]splitSet, error) {
+	sets := []splitSet{}
+	for _, e := range exprs {
+		switch x := e.(type) {
+		case *Expr:
+			sets = append(sets, exactSetOf(x.AST(), x.includePrerelease))
+		case *Range:
+			sets = append(sets, exactSetOf(&RangeNode{Range: x}, x.IncludePrerelease))
+		default:
+			return nil, fmt.Errorf("unsupported expression type: %T", e)
+		}
+	}
+	return sets, nil
+}
+
+// exactVersionSet returns the versions contained in the range, including pre-releases. Unlike
+// versionSet, x-ranges are compared ignoring pre-releases, as Range.Contains does
+func (r *Range) exactVersionSet() versionSet {
+	i := interval{min: bound{v: lowestPreRelease(), inclusive: true}}
+	if v := r.MinVersion; v != nil {
+		switch {
+		case v.IsFixed():
+			i.min = bound{v: v.Version, inclusive: r.AllowMinEquality}
+		case v.anyMajor:
+			if !r.AllowMinEquality {
+				return versionSet{}
+			}
+		case v.anyMinor && r.AllowMinEquality:
+			i.min = bound{v: preReleaseFloor(v.Major, 0, 0), inclusive: true}
+		case v.anyMinor:
+			i.min = bound{v: preReleaseFloor(v.Major+1, 0, 0), inclusive: true}
+		case r.AllowMinEquality:
+			i.min = bound{v: preReleaseFloor(v.Major, v.Minor, 0), inclusive: true}
+		default:
+			i.min = bound{v: preReleaseFloor(v.Major, v.Minor+1, 0), inclusive: true}
+		}
+	}
+	if v := r.MaxVersion; v != nil {
+		switch {
+		case v.IsFixed():
+			i.max = bound{v: v.Version, inclusive: r.AllowMaxEquality}
+		case v.anyMajor:
+			if !r.AllowMaxEquality {
+				return versionSet{}
+			}
+		case v.anyMinor && r.AllowMaxEquality:
+			i.max = bound{v: preReleaseFloor(v.Major+1, 0, 0), inclusive: false}
+		case v.anyMinor:
+			i.max = bound{v: preReleaseFloor(v.Major, 0, 0), inclusive: false}
+		case r.AllowMaxEquality:
+			i.max = bound{v: preReleaseFloor(v.Major, v.Minor+1, 0), inclusive: false}
+		default:
+			i.max = bound{v: preReleaseFloor(v.Major, v.Minor, 0), inclusive: false}
+		}
+	}
+	return versionSet{i}.normalize()
+}
+
+// preReleaseSet returns the pre-release versions allowed by the range
+func (r *Range) preReleaseSet() versionSet {
+	if r.IncludePrerelease {
+		return fullSplitSet().preReleases
+	}
+	s := versionSet{}
+	for _, limit := range []*GlobVersion{r.MinVersion, r.MaxVersion} {
+		if limit != nil && limit.PreRelease != "" && limit.IsFixed() {
+			s = s.union(versionSet{{
+				min: bound{v: preReleaseFloor(limit.Major, limit.Minor, limit.Patch), inclusive: true},
+				max: bound{v: NewVersion(limit.Major, limit.Minor, limit.Patch), inclusive: false},
+			}})
+		}
+	}
+	return s
+}
+
+// allowedPreReleases returns the pre-release versions allowed by the ranges joined by AND to n,
+// following allowsPreRelease
+func allowedPreReleases(n Node) versionSet {
+	s := versionSet{}
+	switch n := n.(type) {
+	case *RangeNode:
+		s = n.Range.preReleaseSet()
+	case *AndNode:
+		for _, o := range n.Operands {
+			s = s.union(allowedPreReleases(o))
+		}
+	}
+	return s
+}
+
+// exactSetOf returns the versions, including pre-releases, matched by n when evaluated with
+// preReleaseAllowed
+func exactSetOf(n Node, preReleaseAllowed bool) splitSet {
+	switch n := n.(type) {
+	case *RangeNode:
+		s := n.Range.exactVersionSet()
+		if preReleaseAllowed {
+			return splitSet{releases: s, preReleases: s}
+		}
+		return splitSet{releases: s, preReleases: s.intersect(n.Range.preReleaseSet())}
+	case *BranchNode:
+		return splitSet{}
+	case *NotNode:
+		s := exactSetOf(n.Operand, true).complement()
+		if !preReleaseAllowed {
+			s.preReleases = versionSet{}
+		}
+		return s
+	case *OrNode:
+		s := splitSet{}
+		for _, o := range n.Operands {
+			s = s.union(exactSetOf(o, preReleaseAllowed))
+		}
+		return s
+	case *AndNode:
+		allowed, rest := fullSplitSet(), fullSplitSet()
+		for _, o := range n.Operands {
+			allowed = allowed.intersect(exactSetOf(o, true))
+			if !preReleaseAllowed {
+				rest = rest.intersect(exactSetOf(o, false))
+			}
+		}
+		if preReleaseAllowed {
+			return allowed
+		}
+		if len(n.Operands) == 0 {
+			return splitSet{releases: allowed.releases}
+		}
+		// Pre-releases allowed by any of the ranges are evaluated as if they were always allowed
+		preReleases := allowedPreReleases(n)
+		rest.preReleases = preReleases.intersect(allowed.preReleases).union(
+			preReleases.complementFrom(bound{v: lowestPreRelease(), inclusive: true}).intersect(rest.preReleases))
+		return rest
+	default:
+		panic(fmt.Errorf(`Unsupported element type %T`, n))
+	}
+}
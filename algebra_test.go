@@ -0,0 +1,471 @@
+package semver
+
+import (
+	"fmt"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+type exprPair struct {
+	e1 string
+	e2 string
+}
+
+var intersectTestBattery = map[exprPair]string{
+	{"^1.2.0", ">=1.5.0 || <1.0.0"}:          ">=1.5.0 <2.0.0",
+	{"~1.2.3", "^1.0.0"}:                     ">=1.2.3 <1.3.0",
+	{">=1.0.0 <2.0.0", ">=2.0.0"}:            "<0.0.0",
+	{"<=2.0.0", ">=2.0.0"}:                   "2.0.0",
+	{"1.x || >=3.0.0", "1.5.0 - 3.2.0"}:      ">=1.5.0 <2.0.0 || >=3.0.0 <=3.2.0",
+	{"*", "1.3"}:                             ">=1.3.0 <1.4.0",
+	{">*.*.*", "*"}:                          "<0.0.0",
+	{"<1.*.*", ">0.5"}:                       ">=0.6.0 <1.0.0",
+	{">1.2.3 <1.2.4", ">=1.2.3 <=1.2.4"}:     ">1.2.3 <1.2.4",
+	{"1.2.7 || >=1.2.9 <2.0.0", "<1.2.10"}:   "1.2.7 || >=1.2.9 <1.2.10",
+	{"<1.0.0 || >2.0.0", ">=1.0.0 <=2.0.0"}:  "<0.0.0",
+	{">=0.0.0", "<3.0.0 >=2.0.0 || 1.2.3"}:   "1.2.3 || >=2.0.0 <3.0.0",
+	{"^0.0.2", "^0.0.2 || ^0.3.1 || ^1.3.4"}: ">=0.0.2 <0.0.3",
+}
+
+var unionTestBattery = map[exprPair]string{
+	{"^1.2.0", ">=1.5.0 || <1.0.0"}:       "<1.0.0 || >=1.2.0",
+	{"<1.0.0", ">=1.0.0"}:                 "*",
+	{"<1.0.0", ">1.0.0"}:                  "<1.0.0 || >1.0.0",
+	{"1.0.0 - 1.5.0", "1.4.0 - 2.0.0"}:    ">=1.0.0 <=2.0.0",
+	{"1.x", "3.x"}:                        ">=1.0.0 <2.0.0 || >=3.0.0 <4.0.0",
+	{"1.x", "2.x"}:                        ">=1.0.0 <3.0.0",
+	{">*.*.*", "<*.*.*"}:                  "<0.0.0",
+	{"~1.2.3 || ~1.2.4", "1.2.3 - 1.2.6"}: ">=1.2.3 <1.3.0",
+}
+
+var complementTestBattery = map[string]string{
+	"*":                 "<0.0.0",
+	"<0.0.0":            "*",
+	"^1.2.0":            "<1.2.0 || >=2.0.0",
+	">1.0.0":            "<=1.0.0",
+	"1.2.3":             "<1.2.3 || >1.2.3",
+	"1.x || >=3.0.0":    "<1.0.0 || >=2.0.0 <3.0.0",
+	"1.3.2 - 1.4.1":     "<1.3.2 || >1.4.1",
+	"<=1.2.7 || >2.0.0": ">1.2.7 <=2.0.0",
+}
+
+func TestIntersect(t *testing.T) {
+	for pair, expected := range intersectTestBattery {
+		e1 := MustParseExpr(pair.e1)
+		e2 := MustParseExpr(pair.e2)
+		for _, operands := range [][2]Expression{{e1, e2}, {e2, e1}} {
+			res, err := Intersect(operands[0], operands[1])
+			if err != nil || res.String() != expected {
+				t.Errorf("Expected intersection of %q and %q to be %q but got %q (%v)", pair.e1, pair.e2, expected, res, err)
+				continue
+			}
+			if empty, err := IsEmpty(res); expected == "<0.0.0" && (err != nil || !empty) {
+				t.Errorf("Expected IsEmpty(%q) to be true", res)
+			}
+		}
+	}
+}
+
+func TestUnion(t *testing.T) {
+	for pair, expected := range unionTestBattery {
+		e1 := MustParseExpr(pair.e1)
+		e2 := MustParseExpr(pair.e2)
+		for _, operands := range [][2]Expression{{e1, e2}, {e2, e1}} {
+			if res, err := Union(operands[0], operands[1]); err != nil || res.String() != expected {
+				t.Errorf("Expected union of %q and %q to be %q but got %q (%v)", pair.e1, pair.e2, expected, res, err)
+			}
+		}
+	}
+}
+
+func TestComplement(t *testing.T) {
+	for exprStr, expected := range complementTestBattery {
+		if res, err := Complement(MustParseExpr(exprStr)); err != nil || res.String() != expected {
+			t.Errorf("Expected complement of %q to be %q but got %q (%v)", exprStr, expected, res, err)
+		}
+	}
+}
+
+func TestSetOperationsMatchEvaluation(t *testing.T) {
+	versions := []string{
+		"0.0.0", "0.0.1", "0.3.1", "0.9.3", "1.0.0", "1.2.3", "1.2.4", "1.2.7", "1.2.8", "1.3.0",
+		"1.3.2", "1.4.1", "1.4.2", "1.5.0", "2.0.0", "2.4.99", "2.5.0", "3.0.0", "5.45.23", "7.2.3",
+	}
+	exprs := []Expression{}
+	for _, battery := range []map[string]map[string]bool{rangeTestBattery, exprTestBattery} {
+		for exprStr := range battery {
+			exprs = append(exprs, MustParseExpr(exprStr))
+		}
+	}
+	for _, e1 := range exprs {
+		complement, _ := Complement(e1)
+		for _, e2 := range exprs {
+			intersection, _ := Intersect(e1, e2)
+			union, _ := Union(e1, e2)
+			for _, vStr := range versions {
+				v := MustParseVersion(vStr)
+				m1, m2 := e1.Matches(v), e2.Matches(v)
+				if intersection.Matches(v) != (m1 && m2) {
+					t.Errorf("Expected %q (intersection of %q and %q) to evaluate %v to %v", intersection, e1, e2, v, m1 && m2)
+				}
+				if union.Matches(v) != (m1 || m2) {
+					t.Errorf("Expected %q (union of %q and %q) to evaluate %v to %v", union, e1, e2, v, m1 || m2)
+				}
+			}
+		}
+		for _, vStr := range versions {
+			v := MustParseVersion(vStr)
+			if complement.Matches(v) == e1.Matches(v) {
+				t.Errorf("Expected %q (complement of %q) to evaluate %v to %v", complement, e1, v, !e1.Matches(v))
+			}
+		}
+	}
+}
+
+func TestSetOperationsIncludePrerelease(t *testing.T) {
+	e1 := MustParseExpr(">=1.0.0", IncludePrerelease)
+	e2 := MustParseExpr("<2.0.0", IncludePrerelease)
+	v := MustParseVersion("1.5.0-beta")
+	if res, err := Intersect(e1, e2); err != nil || !res.Matches(v) || res.String() != ">=1.0.0 <2.0.0" {
+		t.Errorf("Expected intersection of %q and %q to be %q and match %v but got %q (%v)", e1, e2, ">=1.0.0 <2.0.0", v, res, err)
+	}
+	if res, err := Complement(e1); err != nil || res.String() != "<1.0.0" || !res.Matches(MustParseVersion("1.0.0-rc.1")) {
+		t.Errorf("Expected complement of %q to be %q and match %v but got %q (%v)", e1, "<1.0.0", "1.0.0-rc.1", res, err)
+	}
+	if res := MustParseRange("^1.2.3", IncludePrerelease).Union(MustParseRange("1.2.x", IncludePrerelease)); !res.Matches(MustParseVersion("1.2.0-rc.1")) {
+		t.Errorf("Expected %q to match %v", res, "1.2.0-rc.1")
+	}
+
+	exprs := []Expression{}
+	for exprStr := range includePreReleaseTestBattery {
+		exprs = append(exprs, MustParseExpr(exprStr, IncludePrerelease))
+	}
+	versions := []*Version{}
+	for _, data := range includePreReleaseTestBattery {
+		for vStr := range data {
+			versions = append(versions, MustParseVersion(vStr))
+		}
+	}
+	for _, e1 := range exprs {
+		complement, _ := Complement(e1)
+		for _, e2 := range exprs {
+			intersection, _ := Intersect(e1, e2)
+			union, _ := Union(e1, e2)
+			for _, res := range []Expression{intersection, union} {
+				if parsed := MustParseExpr(res.String(), IncludePrerelease); !reflect.DeepEqual(res, parsed) {
+					t.Errorf("Expected %q to be the expression parsed from its text including pre-releases", res)
+				}
+			}
+			for _, v := range versions {
+				m1, m2 := e1.Matches(v), e2.Matches(v)
+				if intersection.Matches(v) != (m1 && m2) {
+					t.Errorf("Expected %q (intersection of %q and %q) to evaluate %v to %v", intersection, e1, e2, v, m1 && m2)
+				}
+				if union.Matches(v) != (m1 || m2) {
+					t.Errorf("Expected %q (union of %q and %q) to evaluate %v to %v", union, e1, e2, v, m1 || m2)
+				}
+			}
+		}
+		for _, v := range versions {
+			if complement.Matches(v) == e1.Matches(v) {
+				t.Errorf("Expected %q (complement of %q) to evaluate %v to %v", complement, e1, v, !e1.Matches(v))
+			}
+		}
+	}
+}
+
+func TestRangeSetOperations(t *testing.T) {
+	r1 := MustParseRange("^1.2.0")
+	r2 := MustParseRange("<1.4.0")
+	if res := r1.Intersect(r2).String(); res != ">=1.2.0 <1.4.0" {
+		t.Errorf("Expected intersection of ranges to be %q but got %q", ">=1.2.0 <1.4.0", res)
+	}
+	if res := r1.Union(r2).String(); res != "<2.0.0" {
+		t.Errorf("Expected union of ranges to be %q but got %q", "<2.0.0", res)
+	}
+	if res := r1.Complement().String(); res != "<1.2.0 || >=2.0.0" {
+		t.Errorf("Expected complement of range to be %q but got %q", "<1.2.0 || >=2.0.0", res)
+	}
+	if r1.IsEmpty() {
+		t.Errorf("Expected %v to not be empty", r1)
+	}
+	if !MustParseRange("<*").IsEmpty() {
+		t.Errorf("Expected %q to be empty", "<*")
+	}
+	if MustParseRange("1.2.3-rc.1").IsEmpty() {
+		t.Errorf("Expected %q to not be empty", "1.2.3-rc.1")
+	}
+	emptyTestBattery := map[string]bool{
+		">=1.2.3-rc.1 <1.2.3": false, ">1.2.3-rc.1 <1.2.3-rc.1.0": true, ">1.2.3 <1.2.4": true, "<0.0.0": true,
+	}
+	for str, expected := range emptyTestBattery {
+		if empty, err := IsEmpty(MustParseExpr(str)); err != nil || empty != expected {
+			t.Errorf("Expected IsEmpty(%q) to be %v", str, expected)
+		}
+	}
+}
+
+var subsetTestBattery = map[exprPair]bool{
+	{"~1.2.3", "^1.0.0"}:                     true,
+	{"^1.0.0", "~1.2.3"}:                     false,
+	{"1.2.3", "1.2.x"}:                       true,
+	{"1.2.x", "1.x"}:                         true,
+	{"1.x", "1.2.x"}:                         false,
+	{"1.3.2 - 1.4", "^1.3.0"}:                true,
+	{"1.3.2 - 2", "^1.3.0"}:                  false,
+	{"1.5.x || 2.1.0 - 2.3.0", "^1.0 || ^2"}: true,
+	{"1.5.x || 3.1.0", "^1.0 || ^2"}:         false,
+	{">=1.2.7 <1.3.0", "1.2.7 || >1.2.7 <2"}: true,
+	{"*", ">=0.0.0"}:                         true,
+	{"<*", "1.0.0"}:                          true,
+	{">1.0.0", "<2.0.0"}:                     false,
+	{"=1.2.3-rc.1", "^1.2.0"}:                false,
+	{"^1.2.3-rc.1", "^1.2.0"}:                false,
+	{"^1.2.0", ">=1.2.0-0"}:                  true,
+	{">=1.2.3-rc.1 <1.2.3", "^1.2.3-rc.0"}:   true,
+	{">=1.0.0 || >=1.1.0-beta", ">=1.0.0"}:   false,
+}
+
+var intersectsTestBattery = map[exprPair]bool{
+	{"~1.2.3", "^1.0.0"}:                    true,
+	{"1.x", "2.x"}:                          false,
+	{"1.x || 3.x", "2.x || 3.1.0 - 3.2.0"}:  true,
+	{"1.0.0 - 1.2.0", ">1.2.0"}:             false,
+	{"1.0.0 - 1.2.0", ">=1.2.0"}:            true,
+	{"<1.2.3", ">1.2.3"}:                    false,
+	{"<=1.2.3", ">=1.2.3"}:                  true,
+	{"<*", "*"}:                             false,
+	{"1.2.3-rc.1", "^1.2.0"}:                false,
+	{">=1.2.3-rc.1 <1.2.3", ">=1.2.3-rc.5"}: true,
+	{">=1.2.3-rc.1 <1.2.3", ">=1.2.4"}:      false,
+}
+
+func TestIsSubset(t *testing.T) {
+	for pair, expected := range subsetTestBattery {
+		if res, err := IsSubset(MustParseExpr(pair.e1), MustParseExpr(pair.e2)); err != nil || res != expected {
+			t.Errorf("Expected IsSubset(%q, %q) to be %v", pair.e1, pair.e2, expected)
+		}
+	}
+}
+
+func TestIntersects(t *testing.T) {
+	for pair, expected := range intersectsTestBattery {
+		e1 := MustParseExpr(pair.e1)
+		e2 := MustParseExpr(pair.e2)
+		res1, err1 := Intersects(e1, e2)
+		res2, err2 := Intersects(e2, e1)
+		if err1 != nil || err2 != nil || res1 != expected || res2 != expected {
+			t.Errorf("Expected Intersects(%q, %q) to be %v", pair.e1, pair.e2, expected)
+		}
+	}
+}
+
+func TestSetRelationsMatchEvaluation(t *testing.T) {
+	r := rand.New(rand.NewSource(7))
+	candidates := []*Version{}
+	for i := 0; i < 300; i++ {
+		candidates = append(candidates, MustParseVersion(randomVersionString(r)))
+	}
+	for _, opts := range [][]ParseOption{nil, {IncludePrerelease}} {
+		for _, str1 := range exactSetTestExpressions {
+			e1 := MustParseExpr(str1, opts...)
+			for _, str2 := range exactSetTestExpressions {
+				e2 := MustParseExpr(str2, opts...)
+				subset, _ := IsSubset(e1, e2)
+				intersects, _ := Intersects(e1, e2)
+				for _, v := range candidates {
+					m1, m2 := e1.Matches(v), e2.Matches(v)
+					if subset && m1 && !m2 {
+						t.Errorf("Expected %q not to be a subset of %q, as only the former matches %v", e1, e2, v)
+					}
+					if !intersects && m1 && m2 {
+						t.Errorf("Expected %q to intersect %q, as both match %v", e1, e2, v)
+					}
+				}
+			}
+		}
+	}
+}
+
+var simplifyTestBattery = map[string]string{
+	">=1.0.0 >=1.2.0 <3.0.0 || 1.5.x":      ">=1.2.0 <3.0.0",
+	"1.x || 1.2.x || 1.2.3":                ">=1.0.0 <2.0.0",
+	"^1.0.0 || ^2.0.0 || ^3.0.0":           ">=1.0.0 <4.0.0",
+	"^1.0.0 || ^3.0.0":                     ">=1.0.0 <2.0.0 || >=3.0.0 <4.0.0",
+	"<2.0.0 || >=1.0.0":                    "*",
+	">=2.0.0 <1.0.0 || 1.2.3":              "1.2.3",
+	"(1.x || 2.x) !2.x":                    ">=1.0.0 <2.0.0",
+	">=1.0.0 <=1.0.0":                      "1.0.0",
+	"1.2.3 - 1.2.5 || 1.2.5 - 1.3.0 || <1": "<1.0.0 || >=1.2.3 <=1.3.0",
+	">=1.0.0 || >=1.1.0-beta":              ">=1.0.0 || >=1.1.0-beta <1.1.0",
+	"^1.2.3-rc.1 || ^1.2.0":                ">=1.2.0 <2.0.0 || >=1.2.3-rc.1 <1.2.3",
+	">=1.2.3-rc.1 <=1.2.3-rc.5 || 1.2.3":   ">=1.2.3-rc.1 <=1.2.3-rc.5 || 1.2.3",
+	"1.2.3-rc.1 || 1.2.3-rc.1":             "1.2.3-rc.1",
+}
+
+var equivalentTestBattery = map[exprPair]bool{
+	{"~1.2", ">=1.2.0 <1.3.0"}:                       true,
+	{"^1.2.3", ">=1.2.3 <2.0.0"}:                     true,
+	{"1.x", "1.0.0 - 1"}:                             true,
+	{"1.x || 2.x", ">=1.0.0 <3.0.0"}:                 true,
+	{"!1.2.3", "<1.2.3 || >1.2.3"}:                   true,
+	{">=1.0.0 >=1.2.0 <3.0.0 || 1.5.x", "^1.2 || 2"}: true,
+	{"~1.2", "~1.2.1"}:                               false,
+	{"1.x", ">=1.0.0"}:                               false,
+	{"<=1.2.3", "<1.2.3"}:                            false,
+	{">=1.0.0", ">=1.0.0 || >=1.1.0-beta"}:           false,
+	{"^1.2.3-rc.1", ">=1.2.3-rc.1 <1.2.3 || ^1.2.3"}: true,
+	{"1.2.3-rc.1", "1.2.3-rc.1 || 1.2.3-rc.2"}:       false,
+}
+
+func TestSimplify(t *testing.T) {
+	for str, expected := range simplifyTestBattery {
+		e := MustParseExpr(str)
+		res, err := Simplify(e)
+		if err != nil || res.String() != expected {
+			t.Errorf("Expected %q to be simplified to %q but got %q (%v)", str, expected, res, err)
+			continue
+		}
+		if ok, err := Equivalent(e, res); err != nil || !ok {
+			t.Errorf("Expected %q to be equivalent to its simplified form %q", str, res)
+		}
+	}
+}
+
+func TestSimplifyMatchesEvaluation(t *testing.T) {
+	r := rand.New(rand.NewSource(3))
+	candidates := []*Version{}
+	for i := 0; i < 1000; i++ {
+		candidates = append(candidates, MustParseVersion(randomVersionString(r)))
+	}
+	for _, opts := range [][]ParseOption{nil, {IncludePrerelease}} {
+		for _, str := range exactSetTestExpressions {
+			e := MustParseExpr(str, opts...)
+			res, err := Simplify(e)
+			if err != nil {
+				t.Errorf("Unexpected error simplifying %q: %v", str, err)
+				continue
+			}
+			if res.(*Expr).includePrerelease != (opts != nil) {
+				t.Errorf("Expected %q to keep the parse options of %q", res, str)
+			}
+			if ok, err := Equivalent(e, res); err != nil || !ok {
+				t.Errorf("Expected %q to be equivalent to its simplified form %q", str, res)
+			}
+			for _, v := range candidates {
+				if res.Matches(v) != e.Matches(v) {
+					t.Errorf("Expected %q (simplified form of %q) to evaluate %v to %v", res, str, v, e.Matches(v))
+				}
+			}
+		}
+	}
+}
+
+func TestEquivalent(t *testing.T) {
+	for pair, expected := range equivalentTestBattery {
+		e1 := MustParseExpr(pair.e1)
+		e2 := MustParseExpr(pair.e2)
+		res1, err1 := Equivalent(e1, e2)
+		res2, err2 := Equivalent(e2, e1)
+		if err1 != nil || err2 != nil || res1 != expected || res2 != expected {
+			t.Errorf("Expected Equivalent(%q, %q) to be %v", pair.e1, pair.e2, expected)
+		}
+	}
+}
+
+// customExpression is an Expression implemented outside of the package, not supported by the set operations
+type customExpression struct{}
+
+func (customExpression) Matches(v *Version) bool { return v.Major == 1 }
+func (customExpression) String() string          { return "major 1" }
+
+func TestSetOperationsUnsupportedExpressions(t *testing.T) {
+	e := MustParseExpr("^1.2")
+	others := []Expression{
+		customExpression{}, MustParseGemRequirement("~> 1.2"), MustParseMavenVersionRange("[1.2,2.0)"),
+		MustParsePEP440SpecifierSet("~=1.2"),
+	}
+	for _, other := range others {
+		if _, err := Intersect(e, other); err == nil {
+			t.Errorf("Expected the intersection with %T to fail", other)
+		}
+		if _, err := Union(other, e); err == nil {
+			t.Errorf("Expected the union with %T to fail", other)
+		}
+		if _, err := Complement(other); err == nil {
+			t.Errorf("Expected the complement of %T to fail", other)
+		}
+		if _, err := IsEmpty(other); err == nil {
+			t.Errorf("Expected IsEmpty with %T to fail", other)
+		}
+		if _, err := IsSubset(e, other); err == nil {
+			t.Errorf("Expected IsSubset with %T to fail", other)
+		}
+		if _, err := Canonical(other); err == nil {
+			t.Errorf("Expected the canonical form of %T to fail", other)
+		}
+		if _, err := FormatCargoReq(other); err == nil {
+			t.Errorf("Expected %T to fail to be formatted as a Cargo requirement", other)
+		}
+	}
+}
+
+var exactSetTestExpressions = []string{
+	"", "*", "1.2.3", "=1.2.3-rc.1", "!=1.2.3", ">1.2.3", ">=1.2.3", "<1.2.3", "<=1.2.3",
+	"1.x", "1.2.x", ">1.x", ">=1.2", "<1.x", "<=1.2", ">*", "<*", "^0.0.3", "^0.2.3", "^1.2.3",
+	"~1.2.3", "~1.2", "~1", "^1.2.3-beta.2", "~1.2.3-beta.2", "1.2.3 - 2.3.4", "1.2 - 2.3.4", "1.2.3 - 2",
+	">=1.2.3-alpha.10 <1.2.3-beta", ">1.0.0-alpha.beta <=1.0.0-rc.1", ">=1.0.0-0 <1.0.0", ">=0.0.0-0",
+	"<1.0.0-0", ">=1.2.3-rc.1 <2", ">=1.2.3-rc <1.2.3-rc.1.a", ">1.2.3-a-b <1.2.3-b-", ">=10.100.1000",
+	"<=199.1099.2000-9", ">9.99.999 <10.0.11", "1.2.3 || 2.x || >=3.1.4 <3.2.0", "^1.2 || ^2.3",
+	"!1.2.x", "!(1.x || 3.x)", "!(>=1.2.3-rc.1 <1.2.3)", ">=1.2.3-rc.1 !1.2.3-rc.5", "(>=1 <2) || !(<3)",
+	"1.2.3-rc.1 || !>1.0.0", ">=1.2.3-rc.1 && (<1.2.3-rc.10 || >1.2.3-rc.99) && !=1.2.3-rc.3",
+}
+
+var exactSetTestIdentifiers = []string{
+	"0", "1", "2", "3", "5", "9", "10", "11", "99", "100", "123",
+	"a", "a-b", "alpha", "alpha1", "b", "b-", "beta", "rc", "rc-1", "RC", "-", "--", "0a", "1a", "z", "Z",
+}
+
+func randomVersionString(r *rand.Rand) string {
+	numbers := []int64{0, 0, 1, 1, 2, 2, 3, 9, 10, 11, 99, 100, 199, 1000, 1099, 2000}
+	v := fmt.Sprintf("%d.%d.%d", numbers[r.Intn(len(numbers))], numbers[r.Intn(len(numbers))], numbers[r.Intn(len(numbers))])
+	if r.Intn(2) == 0 {
+		// Most pre-release rules only apply to the limits of the tested expressions
+		v = []string{"0.0.0", "1.0.0", "1.2.3", "2.0.0", "3.1.4"}[r.Intn(5)]
+	}
+	if r.Intn(2) == 0 {
+		v += "-" + exactSetTestIdentifiers[r.Intn(len(exactSetTestIdentifiers))]
+		for r.Intn(3) == 0 {
+			v += "." + exactSetTestIdentifiers[r.Intn(len(exactSetTestIdentifiers))]
+		}
+	}
+	if r.Intn(5) == 0 {
+		v += "+build." + exactSetTestIdentifiers[r.Intn(len(exactSetTestIdentifiers))]
+	}
+	return v
+}
+
+func TestExactSetMatchesExpressions(t *testing.T) {
+	r := rand.New(rand.NewSource(42))
+	candidates := []string{"0.0.0", "0.0.0-0", "1.2.3", "1.2.3-rc.1", "1.2.3-rc.1+b", "2.0.0-0", "10.100.1000"}
+	for i := 0; i < 3000; i++ {
+		candidates = append(candidates, randomVersionString(r))
+	}
+	for _, str := range exactSetTestExpressions {
+		for _, opts := range [][]ParseOption{nil, {IncludePrerelease}} {
+			e := MustParseExpr(str, opts...)
+			s := exactSetOf(e.(*Expr).AST(), opts != nil)
+			for _, vStr := range candidates {
+				v := MustParseVersion(vStr)
+				set := s.releases
+				if v.PreRelease != "" {
+					set = s.preReleases
+				}
+				if expected := e.Matches(v); set.contains(v) != expected {
+					t.Errorf("Expected the exact set of %q (include pre-releases: %v) to contain %q: %v", str, opts != nil, v, expected)
+				}
+			}
+		}
+	}
+}
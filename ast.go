@@ -0,0 +1,193 @@
+package semver
+
+// Span defines the location of a node in the parsed input, as the byte offsets [Start, End)
+type Span struct {
+	Start int
+	End   int
+}
+
+// Node defines an element of a parsed expression
+type Node interface {
+	// Span returns the location of the node in the parsed input
+	Span() Span
+	evaluable
+}
+
+// AndNode matches the versions accepted by all of its operands. An AndNode without
+// operands matches any version
+type AndNode struct {
+	Operands []Node
+	Location Span
+}
+
+// Span returns the location of the node in the parsed input
+func (n *AndNode) Span() Span {
+	return n.Location
+}
+
+func (n *AndNode) evaluate(v *Version, preReleaseAllowed bool) bool {
+	if v.PreRelease != "" && !preReleaseAllowed {
+		if len(n.Operands) == 0 {
+			return false
+		}
+		preReleaseAllowed = allowsPreRelease(n, v)
+	}
+	for _, o := range n.Operands {
+		if !o.evaluate(v, preReleaseAllowed) {
+			return false
+		}
+	}
+	return true
+}
+
+// OrNode matches the versions accepted by any of its operands. An OrNode without
+// operands does not match any version
+type OrNode struct {
+	Operands []Node
+	Location Span
+}
+
+// Span returns the location of the node in the parsed input
+func (n *OrNode) Span() Span {
+	return n.Location
+}
+
+func (n *OrNode) evaluate(v *Version, preReleaseAllowed bool) bool {
+	for _, o := range n.Operands {
+		if o.evaluate(v, preReleaseAllowed) {
+			return true
+		}
+	}
+	return false
+}
+
+// NotNode matches the versions not accepted by its operand
+type NotNode struct {
+	Operand  Node
+	Location Span
+}
+
+// Span returns the location of the node in the parsed input
+func (n *NotNode) Span() Span {
+	return n.Location
+}
+
+// evaluate negates the operand as a plain set of versions. Negations do not allow pre-releases
+// on their own, but they can be combined with ranges allowing them
+func (n *NotNode) evaluate(v *Version, preReleaseAllowed bool) bool {
+	if v.PreRelease != "" && !preReleaseAllowed {
+		return false
+	}
+	return !n.Operand.evaluate(v, true)
+}
+
+// RangeNode matches the versions contained in a Range. Operator contains the
+// range operator used in the input ("-" for hyphen ranges and "" for plain versions)
+type RangeNode struct {
+	Range    *Range
+	Operator string
+	Location Span
+}
+
+// Span returns the location of the node in the parsed input
+func (n *RangeNode) Span() Span {
+	return n.Location
+}
+
+func (n *RangeNode) evaluate(v *Version, preReleaseAllowed bool) bool {
+	return n.Range.evaluate(v, preReleaseAllowed)
+}
+
+// BranchNode refers to a development branch by name, such as "dev-main" in a Composer
+// constraint. Branches are not versions, so it does not match any version
+type BranchNode struct {
+	Name     string
+	Location Span
+}
+
+// Span returns the location of the node in the parsed input
+func (n *BranchNode) Span() Span {
+	return n.Location
+}
+
+func (n *BranchNode) evaluate(v *Version, preReleaseAllowed bool) bool {
+	return false
+}
+
+// allowsPreRelease returns true if any of the ranges in the set of ranges joined by AND to n
+// allows the pre-release version v
+func allowsPreRelease(n Node, v *Version) bool {
+	switch n := n.(type) {
+	case *RangeNode:
+		return n.Range.allowsPreRelease(v)
+	case *AndNode:
+		for _, o := range n.Operands {
+			if allowsPreRelease(o, v) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Visitor defines the interface used to traverse an expression tree with Walk.
+// The Visit method is invoked for each node. If the returned visitor w is not nil,
+// Walk visits each of the children of node with w, followed by a call of w.Visit(nil)
+type Visitor interface {
+	Visit(node Node) (w Visitor)
+}
+
+// Walk traverses an expression tree in depth-first order
+func Walk(v Visitor, node Node) {
+	if v = v.Visit(node); v == nil {
+		return
+	}
+	switch n := node.(type) {
+	case *AndNode:
+		for _, o := range n.Operands {
+			Walk(v, o)
+		}
+	case *OrNode:
+		for _, o := range n.Operands {
+			Walk(v, o)
+		}
+	case *NotNode:
+		Walk(v, n.Operand)
+	}
+	v.Visit(nil)
+}
+
+type inspector func(Node) bool
+
+func (f inspector) Visit(node Node) Visitor {
+	if f(node) {
+		return f
+	}
+	return nil
+}
+
+// Inspect traverses an expression tree in depth-first order, calling f for each node.
+// If f returns true, Inspect also traverses the children of node, followed by a call of f(nil)
+func Inspect(node Node, f func(Node) bool) {
+	Walk(inspector(f), node)
+}
+
+// MustParseAST parses a semver expression string into its syntax tree
+// It panics if str is not well formed
+func MustParseAST(str string, opts ...ParseOption) Node {
+	n, err := ParseAST(str, opts...)
+	if err != nil {
+		panic(err)
+	}
+	return n
+}
+
+// ParseAST parses a semver expression string into its syntax tree
+// Syntax errors are reported as a *ParseError
+func ParseAST(str string, opts ...ParseOption) (Node, error) {
+	p, err := newParser(str, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return p.parse()
+}
@@ -0,0 +1,112 @@
+package semver
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// dumpAST returns a textual representation of the tree, including the source of each node
+func dumpAST(str string, node Node) string {
+	src := func(n Node) string {
+		return str[n.Span().Start:n.Span().End]
+	}
+	list := func(nodes []Node) string {
+		res := []string{}
+		for _, n := range nodes {
+			res = append(res, dumpAST(str, n))
+		}
+		return strings.Join(res, " ")
+	}
+	switch n := node.(type) {
+	case *AndNode:
+		return fmt.Sprintf("(AND %s)", list(n.Operands))
+	case *OrNode:
+		return fmt.Sprintf("(OR %s)", list(n.Operands))
+	case *NotNode:
+		return fmt.Sprintf("(NOT %s)", dumpAST(str, n.Operand))
+	case *RangeNode:
+		return fmt.Sprintf("[%s]%q", n.Operator, src(n))
+	default:
+		return fmt.Sprintf("%T", n)
+	}
+}
+
+var astTestBattery = map[string]string{
+	"1.2.3":                        `[]"1.2.3"`,
+	"  >= 1.2.3  ":                 `[>=]">= 1.2.3"`,
+	">=1.2.7 <1.3.0":               `(AND [>=]">=1.2.7" [<]"<1.3.0")`,
+	"1.2.7 || >=1.2.9 <2.0.0":      `(OR []"1.2.7" (AND [>=]">=1.2.9" [<]"<2.0.0"))`,
+	"1.x || 5.0.0 - 7.2.3":         `(OR []"1.x" [-]"5.0.0 - 7.2.3")`,
+	"(>=1.2 <2.0) || (^3.0)":       `(OR (AND [>=]">=1.2" [<]"<2.0") [^]"^3.0")`,
+	"^1.4 && !1.4.2 && != 1.4.3":   `(AND [^]"^1.4" (NOT []"1.4.2") (NOT [=]"!= 1.4.3"))`,
+	"!(1.x || 2.x) ~1.2 || ~>3.4 ": `(OR (AND (NOT (OR []"1.x" []"2.x")) [~]"~1.2") [~>]"~>3.4")`,
+	"":                             `(AND )`,
+}
+
+func TestParseAST(t *testing.T) {
+	for str, expected := range astTestBattery {
+		n := MustParseAST(str)
+		if res := dumpAST(str, n); res != expected {
+			t.Errorf("Expected the AST of %q to be %s but got %s", str, expected, res)
+		}
+	}
+}
+
+func TestASTSpans(t *testing.T) {
+	str := "!(1.x || 2.x) ~1.2"
+	spans := []Span{}
+	Inspect(MustParseAST(str), func(n Node) bool {
+		if n != nil {
+			spans = append(spans, n.Span())
+		}
+		return true
+	})
+	expected := []Span{{0, 18}, {0, 13}, {2, 12}, {2, 5}, {9, 12}, {14, 18}}
+	if fmt.Sprint(spans) != fmt.Sprint(expected) {
+		t.Errorf("Expected the spans of %q to be %v but got %v", str, expected, spans)
+	}
+}
+
+// unboundedChecker reports the ranges without an upper limit
+type unboundedChecker struct {
+	found []string
+	str   string
+}
+
+func (c *unboundedChecker) Visit(node Node) Visitor {
+	if n, ok := node.(*RangeNode); ok && n.Range.MaxVersion == nil {
+		c.found = append(c.found, c.str[n.Span().Start:n.Span().End])
+	}
+	return c
+}
+
+func TestWalk(t *testing.T) {
+	for str, expected := range map[string][]string{
+		">=1.2.3":                 {">=1.2.3"},
+		">=1.2.3 <2.0.0":          {">=1.2.3"},
+		"^1.2.3 || >1.0 || <=3.0": {">1.0"},
+		"~1.2.3 || 1.x":           nil,
+	} {
+		c := &unboundedChecker{str: str}
+		Walk(c, MustParseAST(str))
+		if fmt.Sprint(c.found) != fmt.Sprint(expected) {
+			t.Errorf("Expected the unbounded ranges of %q to be %v but got %v", str, expected, c.found)
+		}
+	}
+}
+
+func TestInspectSkipsChildren(t *testing.T) {
+	count := 0
+	Inspect(MustParseAST("1.x || !(2.x 3.x)"), func(n Node) bool {
+		if n != nil {
+			count++
+		}
+		_, isNot := n.(*NotNode)
+		return !isNot
+	})
+	// OR, 1.x and NOT
+	if count != 3 {
+		t.Errorf("Expected to visit 3 nodes but visited %d", count)
+	}
+}
@@ -0,0 +1,355 @@
+package semver
+
+import (
+	"fmt"
+	"strings"
+)
+
+// cargoComparator describes a comparator of a Cargo requirement. Missing and wildcard
+// components are set to -1
+type cargoComparator struct {
+	operator   string
+	parts      []int64
+	preRelease string
+	wildcard   bool
+	location   Span
+}
+
+type cargoParser struct {
+	input string
+	pos   int
+}
+
+func (p *cargoParser) errorAt(pos int, expected string) *ParseError {
+	token := ""
+	if pos < len(p.input) {
+		token = p.input[pos : pos+1]
+	}
+	return &ParseError{Input: p.input, Offset: pos, Token: token, Expected: expected}
+}
+
+func (p *cargoParser) skipSpaces() {
+	for p.pos < len(p.input) && isSpace(p.input[p.pos]) {
+		p.pos++
+	}
+}
+
+func (p *cargoParser) peek() byte {
+	if p.pos < len(p.input) {
+		return p.input[p.pos]
+	}
+	return 0
+}
+
+// parseNumber parses a number without leading zeros
+func (p *cargoParser) parseNumber() (string, error) {
+	start := p.pos
+	for p.pos < len(p.input) && p.input[p.pos] >= '0' && p.input[p.pos] <= '9' {
+		p.pos++
+	}
+	switch n := p.input[start:p.pos]; {
+	case n == "":
+		return "", p.errorAt(start, "number")
+	case len(n) > 1 && n[0] == '0':
+		return "", p.errorAt(start, "number without leading zeros")
+	default:
+		return n, nil
+	}
+}
+
+func (p *cargoParser) parsePreRelease() (string, error) {
+	ids := []string{}
+	for {
+		start := p.pos
+		for p.pos < len(p.input) && strings.IndexByte(identifierChars, p.input[p.pos]) >= 0 {
+			p.pos++
+		}
+		switch id := p.input[start:p.pos]; {
+		case id == "":
+			return "", p.errorAt(start, "pre-release identifier")
+		case isNumeric(id) && len(id) > 1 && id[0] == '0':
+			return "", p.errorAt(start, "pre-release identifier without leading zeros")
+		default:
+			ids = append(ids, id)
+		}
+		if p.peek() != '.' {
+			return strings.Join(ids, "."), nil
+		}
+		p.pos++
+	}
+}
+
+func (p *cargoParser) parseComparator() (*cargoComparator, error) {
+	p.skipSpaces()
+	c := &cargoComparator{location: Span{Start: p.pos}}
+	for _, op := range []string{">=", "<=", ">", "<", "=", "~", "^"} {
+		if strings.HasPrefix(p.input[p.pos:], op) {
+			c.operator = op
+			p.pos += len(op)
+			break
+		}
+	}
+	p.skipSpaces()
+	for len(c.parts) < 3 {
+		if len(c.parts) > 0 {
+			if p.peek() != '.' {
+				break
+			}
+			p.pos++
+		}
+		switch ch := p.peek(); {
+		case ch == '*' || ch == 'x' || ch == 'X':
+			p.pos++
+			c.wildcard = true
+			c.parts = append(c.parts, -1)
+		case c.wildcard:
+			// Wildcards can only be followed by other wildcards
+			return nil, p.errorAt(p.pos, "wildcard")
+		default:
+			n, err := p.parseNumber()
+			if err != nil {
+				return nil, err
+			}
+			c.parts = append(c.parts, toInt(n))
+		}
+	}
+	if len(c.parts) == 3 && !c.wildcard && p.peek() == '-' {
+		p.pos++
+		pr, err := p.parsePreRelease()
+		if err != nil {
+			return nil, err
+		}
+		c.preRelease = pr
+	}
+	c.location.End = p.pos
+	for len(c.parts) < 3 {
+		c.parts = append(c.parts, -1)
+	}
+	return c, nil
+}
+
+func (p *cargoParser) parse() ([]*cargoComparator, error) {
+	list := []*cargoComparator{}
+	for {
+		c, err := p.parseComparator()
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, c)
+		p.skipSpaces()
+		switch {
+		case p.pos == len(p.input):
+			return list, nil
+		case p.peek() != ',':
+			return nil, p.errorAt(p.pos, `"," or end of input`)
+		}
+		p.pos++
+	}
+}
+
+// concrete returns the number of components that are not missing or wildcards
+func (c *cargoComparator) concrete() int {
+	n := 0
+	for n < 3 && c.parts[n] >= 0 {
+		n++
+	}
+	return n
+}
+
+// lowest returns the lowest version matching the partial version of the comparator
+func (c *cargoComparator) lowest() *GlobVersion {
+	d := make([]int64, 3)
+	for i, n := range c.parts {
+		if n >= 0 {
+			d[i] = n
+		}
+	}
+	return &GlobVersion{Version: NewVersion(d[0], d[1], d[2], c.preRelease)}
+}
+
+// bump returns the lowest version greater than any version matching the first n components
+func (c *cargoComparator) bump(n int) *GlobVersion {
+	d := make([]int64, 3)
+	copy(d, c.parts[:n])
+	d[n-1]++
+	return newGlobVersion(d[0], d[1], d[2])
+}
+
+// toRange returns the Range containing the versions matched by the comparator
+func (c *cargoComparator) toRange() *Range {
+	n := c.concrete()
+	nothing := newGlobVersion(-1, -1, -1)
+	r := &Range{}
+	// partial returns the versions matching the first n components (=I.J)
+	partial := func() *Range {
+		if n == 0 {
+			return r
+		}
+		r.MinVersion, r.AllowMinEquality = c.lowest(), true
+		if n == 3 {
+			r.MaxVersion, r.AllowMaxEquality = c.lowest(), true
+		} else {
+			r.MaxVersion = c.bump(n)
+		}
+		return r
+	}
+	switch c.operator {
+	case "=":
+		return partial()
+	case "", "^":
+		if c.wildcard && c.operator == "" {
+			// Wildcard requirements match the rest of components: 1.2.* is =1.2
+			return partial()
+		}
+		switch {
+		case n == 0:
+			return r
+		case c.parts[0] > 0:
+			r.MaxVersion = c.bump(1)
+		case n == 1:
+			// ^0 is =0
+			return partial()
+		case c.parts[1] > 0:
+			r.MaxVersion = c.bump(2)
+		case n == 2:
+			// ^0.0 is =0.0
+			return partial()
+		default:
+			r.MaxVersion = c.bump(3)
+		}
+		r.MinVersion, r.AllowMinEquality = c.lowest(), true
+	case "~":
+		if n < 3 {
+			return partial()
+		}
+		r.MinVersion, r.AllowMinEquality, r.MaxVersion = c.lowest(), true, c.bump(2)
+	case ">":
+		switch n {
+		case 0:
+			r.MinVersion = nothing
+		case 3:
+			r.MinVersion = c.lowest()
+		default:
+			r.MinVersion, r.AllowMinEquality = c.bump(n), true
+		}
+	case ">=":
+		if n > 0 {
+			r.MinVersion, r.AllowMinEquality = c.lowest(), true
+		}
+	case "<":
+		if n == 0 {
+			r.MaxVersion = nothing
+		} else {
+			r.MaxVersion = c.lowest()
+		}
+	case "<=":
+		switch n {
+		case 0:
+		case 3:
+			r.MaxVersion, r.AllowMaxEquality = c.lowest(), true
+		default:
+			r.MaxVersion = c.bump(n)
+		}
+	}
+	return r
+}
+
+// MustParseCargoReq parses a Cargo version requirement
+// Panics if it cannot be parsed
+func MustParseCargoReq(str string, opts ...ParseOption) Expression {
+	if e, err := ParseCargoReq(str, opts...); err != nil {
+		panic(err)
+	} else {
+		return e
+	}
+}
+
+// ParseCargoReq parses a version requirement using the syntax of Cargo, the Rust package manager,
+// such as "1.2.3" or ">= 1.2, < 1.5". It differs from ParseExpr in that:
+//
+//   - Comparators are separated by commas, and there is no "||" operator.
+//   - A bare version is equivalent to "^": "1.2.3" is ">=1.2.3 <2.0.0", not "=1.2.3".
+//   - Partial versions are allowed with any operator: "^0.0" is ">=0.0.0 <0.1.0" and "=1.2" is ">=1.2.0 <1.3.0".
+//   - Versions cannot have a "v" prefix, leading zeros or build metadata.
+//
+// Pre-release versions follow the same rules as ParseExpr. The returned Expression is an *Expr,
+// whose syntax tree contains a RangeNode for each comparator, with the Cargo operator
+func ParseCargoReq(str string, opts ...ParseOption) (Expression, error) {
+	p := &cargoParser{input: str}
+	comparators, err := p.parse()
+	if err != nil {
+		return nil, err
+	}
+	e := &Expr{str: str}
+	for _, opt := range opts {
+		opt(&e.parseConfig)
+	}
+	root := &AndNode{Location: Span{Start: 0, End: len(str)}}
+	for _, c := range comparators {
+		r := c.toRange()
+		r.IncludePrerelease = e.includePrerelease
+		root.Operands = append(root.Operands, &RangeNode{Range: r, Operator: c.operator, Location: c.location})
+	}
+	e.root = root
+	return e, nil
+}
+
+// cargoCandidates returns the short forms that may describe the interval in a Cargo requirement
+func (i interval) cargoCandidates() []string {
+	if i.max.v == nil || i.max.inclusive || !i.min.inclusive || i.min.v.PreRelease != "" || i.max.v.PreRelease != "" {
+		return nil
+	}
+	v := i.min.v
+	forms := []string{fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)}
+	if v.Patch == 0 {
+		forms = append(forms, fmt.Sprintf("%d.%d", v.Major, v.Minor), fmt.Sprintf("%d.%d.*", v.Major, v.Minor))
+		if v.Minor == 0 {
+			forms = append(forms, fmt.Sprintf("%d", v.Major), fmt.Sprintf("%d.*", v.Major))
+		}
+	}
+	list := []string{}
+	for _, f := range forms {
+		list = append(list, f, "~"+f, "="+f)
+	}
+	return list
+}
+
+// cargoString returns the shortest Cargo requirement describing exactly the interval
+func (i interval) cargoString() string {
+	var str string
+	switch {
+	case i.isUnboundedBelow() && i.max.v == nil:
+		return "*"
+	case i.isPoint():
+		return "=" + i.min.v.String()
+	default:
+		str = strings.Replace(i.String(), " ", ", ", -1)
+	}
+	for _, candidate := range i.cargoCandidates() {
+		if len(candidate) >= len(str) {
+			continue
+		}
+		if e, err := ParseCargoReq(candidate); err == nil && nodeSet(e.(*Expr).AST()).equal(versionSet{i}) {
+			str = candidate
+		}
+	}
+	return str
+}
+
+// FormatCargoReq returns the shortest Cargo requirement matching the same release versions as e.
+// Cargo requirements cannot describe unions, so it fails if e is not a single interval, or if it is
+// not an expression returned by ParseExpr or a range
+func FormatCargoReq(e Expression) (string, error) {
+	s, err := setOf(e)
+	if err != nil {
+		return "", err
+	}
+	switch len(s) {
+	case 0:
+		return "<0.0.0", nil
+	case 1:
+		return s[0].cargoString(), nil
+	default:
+		return "", fmt.Errorf("%q cannot be expressed as a Cargo requirement", s)
+	}
+}
@@ -0,0 +1,161 @@
+package semver
+
+import "testing"
+
+// Examples from the Cargo book ("Specifying dependencies") and the documentation of the semver crate
+var cargoConformanceTable = map[string]string{
+	// Caret requirements
+	"^1.2.3": ">=1.2.3 <2.0.0",
+	"^1.2":   ">=1.2.0 <2.0.0",
+	"^1":     ">=1.0.0 <2.0.0",
+	"^0.2.3": ">=0.2.3 <0.3.0",
+	"^0.2":   ">=0.2.0 <0.3.0",
+	"^0.0.3": ">=0.0.3 <0.0.4",
+	"^0.0":   "<0.1.0",
+	"^0":     "<1.0.0",
+	// Default requirements
+	"1.2.3": ">=1.2.3 <2.0.0",
+	"1.2":   ">=1.2.0 <2.0.0",
+	"1":     ">=1.0.0 <2.0.0",
+	"0.2.3": ">=0.2.3 <0.3.0",
+	"0.0.3": ">=0.0.3 <0.0.4",
+	"0.0":   "<0.1.0",
+	"0":     "<1.0.0",
+	// Tilde requirements
+	"~1.2.3": ">=1.2.3 <1.3.0",
+	"~1.2":   ">=1.2.0 <1.3.0",
+	"~1":     ">=1.0.0 <2.0.0",
+	"~0.2.3": ">=0.2.3 <0.3.0",
+	// Wildcard requirements
+	"*":     "*",
+	"1.*":   ">=1.0.0 <2.0.0",
+	"1.*.*": ">=1.0.0 <2.0.0",
+	"1.2.*": ">=1.2.0 <1.3.0",
+	"1.x":   ">=1.0.0 <2.0.0",
+	// Comparison requirements
+	">= 1.2.0": ">=1.2.0",
+	"> 1":      ">=2.0.0",
+	"< 2":      "<2.0.0",
+	"= 1.2.3":  "1.2.3",
+	"=1.2":     ">=1.2.0 <1.3.0",
+	"=1":       ">=1.0.0 <2.0.0",
+	">1.2.3":   ">1.2.3",
+	">1.2":     ">=1.3.0",
+	">=1.2":    ">=1.2.0",
+	">=1":      ">=1.0.0",
+	"<1.2.3":   "<1.2.3",
+	"<1.2":     "<1.2.0",
+	"<1":       "<1.0.0",
+	"<=1.2.3":  "<=1.2.3",
+	"<=1.2":    "<1.3.0",
+	"<=1":      "<2.0.0",
+	">*":       "<0.0.0",
+	"<*":       "<0.0.0",
+	">=*":      "*",
+	// Multiple requirements
+	">= 1.2, < 1.5":         ">=1.2.0 <1.5.0",
+	">=1.2.3, <1.8.0, ~1.4": ">=1.4.0 <1.5.0",
+	"^1.2, ^2":              "<0.0.0",
+	"  1.2.3 ,<=1.9.0  ":    ">=1.2.3 <=1.9.0",
+	// Pre-releases
+	"^1.2.3-beta.1": ">=1.2.3-beta.1 <2.0.0",
+	"=1.2.3-rc":     "1.2.3-rc",
+}
+
+func TestCargoConformance(t *testing.T) {
+	for req, expected := range cargoConformanceTable {
+		e, err := ParseCargoReq(req)
+		if err != nil {
+			t.Errorf("Expected %q to be a valid Cargo requirement but got %v", req, err)
+			continue
+		}
+		if res, err := Canonical(e); err != nil || res != expected {
+			t.Errorf("Expected Cargo requirement %q to be %q but got %q (%v)", req, expected, res, err)
+		}
+		if e.String() != req {
+			t.Errorf("Expected the string of %q to be the original input but got %q", req, e)
+		}
+	}
+}
+
+func TestCargoMatches(t *testing.T) {
+	for req, data := range map[string]map[string]bool{
+		"1.2.3":                  {"1.2.3": true, "1.9.0": true, "2.0.0": false, "1.2.2": false, "1.3.0-rc.1": false},
+		"^0.0.3":                 {"0.0.3": true, "0.0.4": false, "0.0.3+build": true},
+		"^1.2.3-beta.1":          {"1.2.3-beta.1": true, "1.2.3-beta.2": true, "1.2.3-alpha": false, "1.2.4-beta.1": false, "1.5.0": true},
+		">=1.0.0-rc.1, <2.0.0-0": {"1.0.0-rc.2": true, "2.0.0-alpha": false, "2.0.0-0": false, "1.5.0-rc.1": false, "1.5.0": true},
+		">1, <=1.2.3-rc.2":       {"1.2.3-rc.1": false, "1.2.3-rc.3": false},
+		">=2, <=2.0.0-rc.2":      {"2.0.0-rc.1": false},
+		"*":                      {"0.0.0": true, "10.0.0": true, "1.0.0-rc.1": false},
+	} {
+		e := MustParseCargoReq(req)
+		for v, expected := range data {
+			if e.Matches(MustParseVersion(v)) != expected {
+				t.Errorf("Expected Cargo requirement %q to match %q: %v", req, v, expected)
+			}
+		}
+	}
+	if e := MustParseCargoReq("1.2", IncludePrerelease); !e.Matches(MustParseVersion("1.5.0-rc.1")) {
+		t.Errorf("Expected IncludePrerelease to make Cargo requirements accept pre-releases")
+	}
+}
+
+func TestCargoErrors(t *testing.T) {
+	for req, offset := range map[string]int{
+		"":               0,
+		"  ":             2,
+		"v1.2.3":         0,
+		"01.2":           0,
+		"1.02":           2,
+		"1.2.3-01":       6,
+		"1.2.3-":         6,
+		"1.2.3+build":    5,
+		"1.*.3":          4,
+		"1.2 || 1.3":     4,
+		"1.2 1.3":        4,
+		">=1.2,":         6,
+		">=1.2, ,<2":     7,
+		"~>1.2":          1,
+		"1.2.3.4":        5,
+		"1.2-beta":       3,
+		">= 1.2 < 1.5":   7,
+		"^1.2.3-beta..1": 12,
+	} {
+		_, err := ParseCargoReq(req)
+		if pErr, ok := err.(*ParseError); !ok || pErr.Offset != offset {
+			t.Errorf("Expected Cargo requirement %q to fail at offset %d but got %v", req, offset, err)
+		}
+	}
+}
+
+func TestFormatCargoReq(t *testing.T) {
+	for expr, expected := range map[string]string{
+		"^1.2.3":                "1.2.3",
+		"^0.2":                  "0.2",
+		"~0.0.3":                "~0.0.3",
+		"^0.0.3":                "0.0.3",
+		"~1.2.3":                "~1.2.3",
+		"1.2.x":                 "~1.2",
+		">=1.2.0 <1.5.0":        ">=1.2.0, <1.5.0",
+		"1.2.3":                 "=1.2.3",
+		"1.2.3 - 1.4.5":         ">=1.2.3, <=1.4.5",
+		">1.2.3":                ">1.2.3",
+		"<1.2.3":                "<1.2.3",
+		"*":                     "*",
+		">2 <1":                 "<0.0.0",
+		">=1.2.3-beta <2.0.0":   ">=1.2.3-beta, <2.0.0",
+		"^1.2 && ~1.5 || 1.5.9": "~1.5",
+	} {
+		res, err := FormatCargoReq(MustParseExpr(expr))
+		if err != nil || res != expected {
+			t.Errorf("Expected %q to be formatted as Cargo requirement %q but got %q (%v)", expr, expected, res, err)
+			continue
+		}
+		if ok, err := Equivalent(MustParseCargoReq(res), MustParseExpr(expr)); err != nil || !ok {
+			t.Errorf("Expected Cargo requirement %q to be equivalent to %q", res, expr)
+		}
+	}
+	if _, err := FormatCargoReq(MustParseExpr("^1.2 || ^3")); err == nil {
+		t.Errorf("Expected unions to fail to be formatted as Cargo requirements")
+	}
+}
@@ -0,0 +1,290 @@
+// Command semver exposes the semver library from the command line.
+//
+// Usage:
+//
+//	semver [--loose] [--json] <command> [arguments]
+//
+// Commands:
+//
+//	satisfies <version> <expression>  Exits with 0 if the version satisfies the expression, 1 otherwise
+//	sort [-r]                         Sorts the versions read from stdin (descending with -r)
+//	max -r <expression>               Prints the highest version read from stdin satisfying the expression
+//	inc [--preid id] <kind> <version> Increments the version (major, minor, patch, premajor, preminor,
+//	                                  prepatch or prerelease)
+//	valid <version>...                Prints the normalized versions, exiting with 1 if any is invalid
+//	compare <version> <version>       Prints -1, 0 or 1 if the first version is lower, equal or higher
+//
+// Versions read from stdin are separated by whitespace. Invalid ones are reported and ignored.
+// With --loose, non standard versions such as "1.2-beta" or "1.2.3.4" are accepted, and with --json
+// results are printed as JSON values. Commands exit with 0 on success, 1 when the result is
+// negative or empty and 2 on errors
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/juamedgod/semver"
+)
+
+// Exit codes
+const (
+	exitOK       = 0
+	exitNegative = 1
+	exitError    = 2
+)
+
+const usage = `Usage: semver [--loose] [--json] <command> [arguments]
+
+Commands:
+  satisfies <version> <expression>   Exits with 0 if the version satisfies the expression, 1 otherwise
+  sort [-r]                          Sorts the versions read from stdin (descending with -r)
+  max -r <expression>                Prints the highest version read from stdin satisfying the expression
+  inc [--preid id] <kind> <version>  Increments the version (major, minor, patch, premajor, preminor,
+                                     prepatch or prerelease)
+  valid <version>...                 Prints the normalized versions, exiting with 1 if any is invalid
+  compare <version> <version>        Prints -1, 0 or 1 if the first version is lower, equal or higher
+
+Options:
+  --loose  Accept non standard versions, such as "1.2-beta" or "1.2.3.4"
+  --json   Print results as JSON values
+`
+
+// cli holds the state of an invocation of the tool
+type cli struct {
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+	loose  bool
+	json   bool
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run executes the command described by args and returns its exit code
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	c := &cli{stdin: stdin, stdout: stdout, stderr: stderr}
+	flags := c.flagSet("semver")
+	if err := flags.Parse(args); err != nil {
+		return exitError
+	}
+	if flags.NArg() == 0 {
+		fmt.Fprint(stderr, usage)
+		return exitError
+	}
+	commands := map[string]func(args []string) int{
+		"satisfies": c.satisfies,
+		"sort":      c.sort,
+		"max":       c.max,
+		"inc":       c.inc,
+		"valid":     c.valid,
+		"compare":   c.compare,
+	}
+	command, ok := commands[flags.Arg(0)]
+	if !ok {
+		fmt.Fprintf(stderr, "unknown command %q\n\n%s", flags.Arg(0), usage)
+		return exitError
+	}
+	return command(flags.Args()[1:])
+}
+
+// flagSet returns a set of flags including the global options
+func (c *cli) flagSet(name string) *flag.FlagSet {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.SetOutput(c.stderr)
+	flags.Usage = func() {
+		fmt.Fprint(c.stderr, usage)
+	}
+	flags.BoolVar(&c.loose, "loose", c.loose, "accept non standard versions")
+	flags.BoolVar(&c.json, "json", c.json, "print results as JSON values")
+	return flags
+}
+
+// parseArgs parses the command arguments, requiring n positional arguments (or at least one if n is negative)
+func (c *cli) parseArgs(flags *flag.FlagSet, args []string, n int) bool {
+	if err := flags.Parse(args); err != nil {
+		return false
+	}
+	if (n < 0 && flags.NArg() == 0) || (n >= 0 && flags.NArg() != n) {
+		fmt.Fprintf(c.stderr, "wrong number of arguments for %s\n\n%s", flags.Name(), usage)
+		return false
+	}
+	return true
+}
+
+func (c *cli) parseVersion(str string) (*semver.Version, error) {
+	if c.loose {
+		return semver.ParsePermissiveVersion(str)
+	}
+	return semver.ParseVersion(str)
+}
+
+// readVersions returns the versions read from stdin, reporting and ignoring invalid ones
+func (c *cli) readVersions() ([]*semver.Version, error) {
+	versions := []*semver.Version{}
+	scanner := bufio.NewScanner(c.stdin)
+	scanner.Split(bufio.ScanWords)
+	for scanner.Scan() {
+		v, err := c.parseVersion(scanner.Text())
+		if err != nil {
+			fmt.Fprintf(c.stderr, "ignoring invalid version: %v\n", err)
+			continue
+		}
+		versions = append(versions, v)
+	}
+	return versions, scanner.Err()
+}
+
+// print writes the result, as JSON if requested
+func (c *cli) print(value interface{}) int {
+	if c.json {
+		if err := json.NewEncoder(c.stdout).Encode(value); err != nil {
+			return c.fail(err)
+		}
+		return exitOK
+	}
+	fmt.Fprintln(c.stdout, value)
+	return exitOK
+}
+
+func (c *cli) fail(err error) int {
+	fmt.Fprintf(c.stderr, "semver: %v\n", err)
+	return exitError
+}
+
+func (c *cli) satisfies(args []string) int {
+	flags := c.flagSet("satisfies")
+	if !c.parseArgs(flags, args, 2) {
+		return exitError
+	}
+	v, err := c.parseVersion(flags.Arg(0))
+	if err != nil {
+		return c.fail(err)
+	}
+	e, err := semver.ParseExpr(flags.Arg(1))
+	if err != nil {
+		return c.fail(err)
+	}
+	result := e.Matches(v)
+	if res := c.print(result); res != exitOK || result {
+		return res
+	}
+	return exitNegative
+}
+
+func (c *cli) sort(args []string) int {
+	flags := c.flagSet("sort")
+	reverse := flags.Bool("r", false, "sort in descending order")
+	if !c.parseArgs(flags, args, 0) {
+		return exitError
+	}
+	versions, err := c.readVersions()
+	if err != nil {
+		return c.fail(err)
+	}
+	if *reverse {
+		semver.SortDescending(versions)
+	} else {
+		semver.Sort(versions)
+	}
+	list := []string{}
+	text := ""
+	for _, v := range versions {
+		list = append(list, v.String())
+		text += v.String() + "\n"
+	}
+	if c.json {
+		return c.print(list)
+	}
+	fmt.Fprint(c.stdout, text)
+	return exitOK
+}
+
+func (c *cli) max(args []string) int {
+	flags := c.flagSet("max")
+	expr := flags.String("r", "", "expression the version must satisfy")
+	if !c.parseArgs(flags, args, 0) {
+		return exitError
+	}
+	versions, err := c.readVersions()
+	if err != nil {
+		return c.fail(err)
+	}
+	max, err := semver.MaxSatisfying(versions, *expr)
+	if err != nil {
+		return c.fail(err)
+	}
+	if max == nil {
+		if c.json {
+			c.print(nil)
+		}
+		return exitNegative
+	}
+	return c.print(max.String())
+}
+
+func (c *cli) inc(args []string) int {
+	flags := c.flagSet("inc")
+	preID := flags.String("preid", "", "identifier used for new pre-releases")
+	if !c.parseArgs(flags, args, 2) {
+		return exitError
+	}
+	v, err := c.parseVersion(flags.Arg(1))
+	if err != nil {
+		return c.fail(err)
+	}
+	n, err := v.Inc(semver.IncKind(flags.Arg(0)), semver.PreReleaseID(*preID))
+	if err != nil {
+		return c.fail(err)
+	}
+	return c.print(n.String())
+}
+
+func (c *cli) valid(args []string) int {
+	flags := c.flagSet("valid")
+	if !c.parseArgs(flags, args, -1) {
+		return exitError
+	}
+	result := exitOK
+	list := []interface{}{}
+	text := ""
+	for _, str := range flags.Args() {
+		v, err := c.parseVersion(str)
+		if err != nil {
+			fmt.Fprintf(c.stderr, "invalid version: %v\n", err)
+			list = append(list, nil)
+			result = exitNegative
+			continue
+		}
+		list = append(list, v.String())
+		text += v.String() + "\n"
+	}
+	if c.json {
+		c.print(list)
+	} else {
+		fmt.Fprint(c.stdout, text)
+	}
+	return result
+}
+
+func (c *cli) compare(args []string) int {
+	flags := c.flagSet("compare")
+	if !c.parseArgs(flags, args, 2) {
+		return exitError
+	}
+	v1, err := c.parseVersion(flags.Arg(0))
+	if err != nil {
+		return c.fail(err)
+	}
+	v2, err := c.parseVersion(flags.Arg(1))
+	if err != nil {
+		return c.fail(err)
+	}
+	return c.print(semver.Compare(v1, v2))
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestRun(t *testing.T) {
+	for _, test := range []struct {
+		args   []string
+		stdin  string
+		stdout string
+		code   int
+	}{
+		{args: []string{"satisfies", "1.3.4", "1.2.1 - 1.4.0"}, stdout: "true\n", code: 0},
+		{args: []string{"satisfies", "1.5.0", "1.2.1 - 1.4.0"}, stdout: "false\n", code: 1},
+		{args: []string{"--json", "satisfies", "1.3.4", "^1.2"}, stdout: "true\n", code: 0},
+		{args: []string{"satisfies", "1_2_3", "^1.2"}, code: 2},
+		{args: []string{"--loose", "satisfies", "1_2_3", "^1.2"}, stdout: "true\n", code: 0},
+		{args: []string{"satisfies", "1.2.3", "^1.2 ||"}, code: 2},
+		{args: []string{"sort"}, stdin: "1.10.0 1.2.0\n1.2.0-rc.1\nfoo\n", stdout: "1.2.0-rc.1\n1.2.0\n1.10.0\n", code: 0},
+		{args: []string{"sort", "-r"}, stdin: "1.10.0 1.2.0\n1.2.0-rc.1\n", stdout: "1.10.0\n1.2.0\n1.2.0-rc.1\n", code: 0},
+		{args: []string{"--loose", "sort", "--json"}, stdin: "v2 1.2.3.4", stdout: `["1.2.3","2.0.0"]` + "\n", code: 0},
+		{args: []string{"sort"}, stdin: "", stdout: "", code: 0},
+		{args: []string{"max", "-r", "^1.2"}, stdin: "1.2.0 1.9.9 2.0.0 1.10.0-rc.1", stdout: "1.9.9\n", code: 0},
+		{args: []string{"max", "-r", "^3"}, stdin: "1.2.0 2.0.0", stdout: "", code: 1},
+		{args: []string{"--json", "max", "-r", "^3"}, stdin: "1.2.0 2.0.0", stdout: "null\n", code: 1},
+		{args: []string{"max"}, stdin: "1.2.0 2.0.0-rc.1 1.3.0", stdout: "1.3.0\n", code: 0},
+		{args: []string{"max", "-r", "1.2.3 -"}, stdin: "1.2.0", code: 2},
+		{args: []string{"inc", "minor", "1.2.3"}, stdout: "1.3.0\n", code: 0},
+		{args: []string{"inc", "--preid", "rc", "prerelease", "1.2.3"}, stdout: "1.2.4-rc.0\n", code: 0},
+		{args: []string{"--json", "inc", "major", "1.2.3"}, stdout: `"2.0.0"` + "\n", code: 0},
+		{args: []string{"inc", "foo", "1.2.3"}, code: 2},
+		{args: []string{"inc", "major"}, code: 2},
+		{args: []string{"valid", "1.2.3", "v2.0.0-rc.1"}, stdout: "1.2.3\n2.0.0-rc.1\n", code: 0},
+		{args: []string{"valid", "1.2.3", "foo"}, stdout: "1.2.3\n", code: 1},
+		{args: []string{"--json", "valid", "1.2.3", "foo"}, stdout: `["1.2.3",null]` + "\n", code: 1},
+		{args: []string{"--loose", "valid", "1.2-beta"}, stdout: "1.2.0-beta\n", code: 0},
+		{args: []string{"valid", "1.2-beta"}, code: 1},
+		{args: []string{"valid"}, code: 2},
+		{args: []string{"compare", "1.2.3", "1.10.0"}, stdout: "-1\n", code: 0},
+		{args: []string{"compare", "1.2.3+a", "1.2.3+b"}, stdout: "0\n", code: 0},
+		{args: []string{"--json", "compare", "1.2.3", "1.2.3-rc.1"}, stdout: "1\n", code: 0},
+		{args: []string{"compare", "1.2.3"}, code: 2},
+		{args: []string{}, code: 2},
+		{args: []string{"foo"}, code: 2},
+		{args: []string{"--foo", "valid", "1.2.3"}, code: 2},
+	} {
+		stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+		code := run(test.args, strings.NewReader(test.stdin), stdout, stderr)
+		if code != test.code || stdout.String() != test.stdout {
+			t.Errorf("Expected %q to exit with %d and print %q but got %d and %q (%s)",
+				test.args, test.code, test.stdout, code, stdout, stderr)
+		}
+		if code == 2 && stderr.Len() == 0 {
+			t.Errorf("Expected %q to report an error", test.args)
+		}
+	}
+}
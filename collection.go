@@ -0,0 +1,57 @@
+package semver
+
+import "sort"
+
+// Compare returns -1, 0 or 1 if v1 has respectively lower, equal or higher precedence than v2,
+// following the SemVer 2.0 rules: build metadata is ignored and pre-releases are compared
+// identifier by identifier. A nil version has lower precedence than any other.
+// It can be used directly with sort.Slice or slices.SortFunc
+func Compare(v1 *Version, v2 *Version) int {
+	switch {
+	case v1 == nil && v2 == nil:
+		return 0
+	case v1 == nil:
+		return -1
+	case v2 == nil:
+		return 1
+	}
+	for i, e1 := range v1.split() {
+		if res := compareInt64(e1, v2.split()[i]); res != 0 {
+			return res
+		}
+	}
+	return compareSemverPreReleases(v1.PreRelease, v2.PreRelease)
+}
+
+// Versions defines a list of versions sortable by SemVer precedence. It implements sort.Interface
+type Versions []*Version
+
+func (vs Versions) Len() int           { return len(vs) }
+func (vs Versions) Swap(i, j int)      { vs[i], vs[j] = vs[j], vs[i] }
+func (vs Versions) Less(i, j int) bool { return Compare(vs[i], vs[j]) < 0 }
+
+// Sort sorts the versions in ascending order. Versions with the same precedence,
+// such as those differing only in their build metadata, keep their relative order
+func Sort(versions []*Version) {
+	sort.Stable(Versions(versions))
+}
+
+// SortDescending sorts the versions in descending order. Versions with the same precedence
+// keep their relative order
+func SortDescending(versions []*Version) {
+	sort.Stable(sort.Reverse(Versions(versions)))
+}
+
+// Dedupe returns a sorted copy of versions in which only the first occurrence of each precedence is kept.
+// For example, 1.0.0+build.1 and 1.0.0+build.2 are considered duplicates
+func Dedupe(versions []*Version) []*Version {
+	sorted := append([]*Version{}, versions...)
+	Sort(sorted)
+	result := []*Version{}
+	for _, v := range sorted {
+		if len(result) == 0 || Compare(result[len(result)-1], v) != 0 {
+			result = append(result, v)
+		}
+	}
+	return result
+}
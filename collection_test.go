@@ -0,0 +1,114 @@
+package semver
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+// Precedence example from the SemVer 2.0 specification, extended with numeric edge cases
+var precedenceList = []string{
+	"0.0.0",
+	"0.9.9",
+	"1.0.0-0",
+	"1.0.0-2",
+	"1.0.0-10",
+	"1.0.0-alpha",
+	"1.0.0-alpha.1",
+	"1.0.0-alpha.beta",
+	"1.0.0-beta",
+	"1.0.0-beta.2",
+	"1.0.0-beta.11",
+	"1.0.0-rc.1",
+	"1.0.0-rc.2",
+	"1.0.0-rc.10",
+	"1.0.0",
+	"1.0.1",
+	"1.2.0",
+	"1.10.0",
+	"2.0.0-rc.1",
+	"2.0.0",
+	"10.0.0",
+}
+
+func parseVersionList(list []string) []*Version {
+	result := []*Version{}
+	for _, str := range list {
+		result = append(result, MustParseVersion(str))
+	}
+	return result
+}
+
+func TestCompare(t *testing.T) {
+	versions := parseVersionList(precedenceList)
+	for i, v1 := range versions {
+		for j, v2 := range versions {
+			if res := Compare(v1, v2); res != compareInt(i, j) {
+				t.Errorf("Expected Compare(%q, %q) to be %d but got %d", v1, v2, compareInt(i, j), res)
+			}
+		}
+	}
+	for pair, expected := range map[[2]string]int{
+		{"1.0.0+build.1", "1.0.0+build.2"}:                            0,
+		{"1.0.0-rc.1+a", "1.0.0-rc.1"}:                                0,
+		{"1.0.0-a-b", "1.0.0-a.b"}:                                    1,
+		{"1.0.0-99999999999999999999", "1.0.0-100000000000000000000"}: -1,
+	} {
+		if res := Compare(MustParseVersion(pair[0]), MustParseVersion(pair[1])); res != expected {
+			t.Errorf("Expected Compare(%q, %q) to be %d but got %d", pair[0], pair[1], expected, res)
+		}
+	}
+	if Compare(nil, NewVersion(0, 0, 0)) != -1 || Compare(NewVersion(0, 0, 0), nil) != 1 || Compare(nil, nil) != 0 {
+		t.Errorf("Expected nil versions to have the lowest precedence")
+	}
+}
+
+func shuffledVersions(list []string) []*Version {
+	versions := parseVersionList(list)
+	r := rand.New(rand.NewSource(42))
+	for i := range versions {
+		j := r.Intn(i + 1)
+		versions[i], versions[j] = versions[j], versions[i]
+	}
+	return versions
+}
+
+func TestSort(t *testing.T) {
+	versions := shuffledVersions(precedenceList)
+	Sort(versions)
+	if res := fmt.Sprint(versions); res != fmt.Sprint(precedenceList) {
+		t.Errorf("Expected sorted versions to be %v but got %v", precedenceList, res)
+	}
+	SortDescending(versions)
+	reversed := []string{}
+	for i := len(precedenceList) - 1; i >= 0; i-- {
+		reversed = append(reversed, precedenceList[i])
+	}
+	if res := fmt.Sprint(versions); res != fmt.Sprint(reversed) {
+		t.Errorf("Expected sorted versions to be %v but got %v", reversed, res)
+	}
+}
+
+func TestSortIsStable(t *testing.T) {
+	versions := parseVersionList([]string{"1.0.0+c", "0.1.0", "1.0.0+a", "1.0.0+b"})
+	Sort(versions)
+	if res := fmt.Sprint(versions); res != "[0.1.0 1.0.0+c 1.0.0+a 1.0.0+b]" {
+		t.Errorf("Expected sort to keep the order of versions with the same precedence but got %v", res)
+	}
+	SortDescending(versions)
+	if res := fmt.Sprint(versions); res != "[1.0.0+c 1.0.0+a 1.0.0+b 0.1.0]" {
+		t.Errorf("Expected sort to keep the order of versions with the same precedence but got %v", res)
+	}
+}
+
+func TestDedupe(t *testing.T) {
+	versions := parseVersionList([]string{"1.0.0+c", "2.0.0", "0.1.0", "1.0.0+a", "v2.0.0", "1.0.0-rc.1", "1.0.0-rc.1+x"})
+	res := Dedupe(versions)
+	if strings.Join(strings.Fields(fmt.Sprint(res)), ",") != "[0.1.0,1.0.0-rc.1,1.0.0+c,2.0.0]" {
+		t.Errorf("Expected deduped versions to be [0.1.0 1.0.0-rc.1 1.0.0+c 2.0.0] but got %v", res)
+	}
+	if len(versions) != 7 || versions[0].String() != "1.0.0+c" {
+		t.Errorf("Expected Dedupe to not modify its input but got %v", versions)
+	}
+}
@@ -0,0 +1,387 @@
+package semver
+
+import (
+	"strings"
+)
+
+// composerStabilities contains the stability flags accepted after "@", in lowercase
+var composerStabilities = map[string]bool{"dev": true, "alpha": true, "beta": true, "rc": true, "stable": true}
+
+// composerVersion describes a version of a Composer constraint. Missing and wildcard
+// components are set to -1
+type composerVersion struct {
+	parts      []int64
+	preRelease string
+	wildcard   bool
+}
+
+type composerParser struct {
+	cargoParser
+}
+
+// isSeparator returns true if the parser is at the end of a version
+func (p *composerParser) isSeparator() bool {
+	ch := p.peek()
+	return ch == 0 || isSpace(ch) || ch == ',' || ch == '|'
+}
+
+func (p *composerParser) parseNumber() (int64, error) {
+	start := p.pos
+	for p.pos < len(p.input) && p.input[p.pos] >= '0' && p.input[p.pos] <= '9' {
+		p.pos++
+	}
+	if p.pos == start {
+		return 0, p.errorAt(start, "number")
+	}
+	return toInt(p.input[start:p.pos]), nil
+}
+
+// parseStability parses an optional stability flag, such as "@dev". Flags only affect
+// the resolution of dependencies, so they are ignored
+func (p *composerParser) parseStability() error {
+	if p.peek() != '@' {
+		return nil
+	}
+	p.pos++
+	start := p.pos
+	for !p.isSeparator() {
+		p.pos++
+	}
+	if !composerStabilities[strings.ToLower(p.input[start:p.pos])] {
+		return p.errorAt(start, "stability flag")
+	}
+	return nil
+}
+
+// parseVersion parses a version, which may contain wildcards and a stability flag
+func (p *composerParser) parseVersion() (*composerVersion, error) {
+	c := &composerVersion{}
+	if p.peek() == '@' {
+		// A stability flag on its own is equivalent to "*"
+		c.parts, c.wildcard = []int64{-1, -1, -1}, true
+		return c, p.parseStability()
+	}
+	if ch := p.peek(); ch == 'v' || ch == 'V' {
+		p.pos++
+	}
+	for len(c.parts) < 3 {
+		if len(c.parts) > 0 {
+			if p.peek() != '.' {
+				break
+			}
+			p.pos++
+		}
+		switch ch := p.peek(); {
+		case ch == '*' || ch == 'x' || ch == 'X':
+			p.pos++
+			c.wildcard = true
+			c.parts = append(c.parts, -1)
+		case c.wildcard:
+			// Wildcards can only be followed by other wildcards
+			return nil, p.errorAt(p.pos, "wildcard")
+		default:
+			n, err := p.parseNumber()
+			if err != nil {
+				return nil, err
+			}
+			c.parts = append(c.parts, n)
+		}
+	}
+	if p.peek() == '-' {
+		p.pos++
+		if strings.HasPrefix(strings.ToLower(p.input[p.pos:]), "dev") {
+			p.pos += len("dev")
+			if !p.isSeparator() && p.peek() != '@' {
+				return nil, p.errorAt(p.pos, "end of version")
+			}
+			// "-dev" is the lowest stability. On a wildcard it names the development
+			// branch of a series, such as "1.0.x-dev"
+			if !c.wildcard {
+				c.preRelease = "0"
+			}
+		} else if c.wildcard {
+			return nil, p.errorAt(p.pos, `"dev"`)
+		} else {
+			pr, err := p.parsePreRelease()
+			if err != nil {
+				return nil, err
+			}
+			c.preRelease = pr
+		}
+	}
+	if p.peek() == '+' {
+		// Build metadata is ignored
+		p.pos++
+		if _, err := p.parsePreRelease(); err != nil {
+			return nil, err
+		}
+	}
+	if err := p.parseStability(); err != nil {
+		return nil, err
+	}
+	if !p.isSeparator() {
+		return nil, p.errorAt(p.pos, "end of version")
+	}
+	for len(c.parts) < 3 {
+		c.parts = append(c.parts, -1)
+	}
+	return c, nil
+}
+
+// parseTerm parses a version preceded by an optional operator, a hyphen range or a branch name
+func (p *composerParser) parseTerm() (Node, error) {
+	start := p.pos
+	if strings.HasPrefix(p.input[p.pos:], "dev-") {
+		p.pos += len("dev-")
+		for !p.isSeparator() {
+			p.pos++
+		}
+		if p.pos == start+len("dev-") {
+			return nil, p.errorAt(p.pos, "branch name")
+		}
+		return &BranchNode{Name: p.input[start+len("dev-") : p.pos], Location: Span{Start: start, End: p.pos}}, nil
+	}
+	operator := ""
+	for _, op := range []string{">=", "<=", "<>", "!=", "==", "~>", ">", "<", "=", "~", "^"} {
+		if strings.HasPrefix(p.input[p.pos:], op) {
+			operator = op
+			p.pos += len(op)
+			break
+		}
+	}
+	p.skipSpaces()
+	c, err := p.parseVersion()
+	if err != nil {
+		return nil, err
+	}
+	var to *composerVersion
+	if operator == "" {
+		// Hyphen ranges require spaces around the hyphen
+		end := p.pos
+		p.skipSpaces()
+		if p.pos > end && p.peek() == '-' && p.pos+1 < len(p.input) && isSpace(p.input[p.pos+1]) {
+			p.pos++
+			p.skipSpaces()
+			if to, err = p.parseVersion(); err != nil {
+				return nil, err
+			}
+			operator = "-"
+		} else {
+			p.pos = end
+		}
+	}
+	location := Span{Start: start, End: p.pos}
+	r := c.toRange(operator, to)
+	r.IncludePrerelease = true
+	n := &RangeNode{Range: r, Operator: operator, Location: location}
+	if operator == "!=" || operator == "<>" {
+		return &NotNode{Operand: n, Location: location}, nil
+	}
+	return n, nil
+}
+
+// parseAnd parses a list of terms separated by commas or spaces
+func (p *composerParser) parseAnd() (Node, error) {
+	p.skipSpaces()
+	start := p.pos
+	operands := []Node{}
+	for {
+		n, err := p.parseTerm()
+		if err != nil {
+			return nil, err
+		}
+		operands = append(operands, n)
+		end := p.pos
+		p.skipSpaces()
+		switch {
+		case p.pos == len(p.input) || p.peek() == '|':
+			if len(operands) == 1 {
+				return n, nil
+			}
+			return &AndNode{Operands: operands, Location: Span{Start: start, End: end}}, nil
+		case p.peek() == ',':
+			p.pos++
+			p.skipSpaces()
+		}
+	}
+}
+
+// parse parses a list of alternatives separated by "|" or "||"
+func (p *composerParser) parse() (Node, error) {
+	p.skipSpaces()
+	start := p.pos
+	operands := []Node{}
+	for {
+		n, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		operands = append(operands, n)
+		if p.pos == len(p.input) {
+			break
+		}
+		p.pos++
+		if p.peek() == '|' {
+			p.pos++
+		}
+	}
+	if len(operands) == 1 {
+		return operands[0], nil
+	}
+	return &OrNode{Operands: operands, Location: Span{Start: start, End: operands[len(operands)-1].Span().End}}, nil
+}
+
+// concrete returns the number of components that are not missing or wildcards
+func (c *composerVersion) concrete() int {
+	n := 0
+	for n < 3 && c.parts[n] >= 0 {
+		n++
+	}
+	return n
+}
+
+// exact returns the version, filling missing components with zeros
+func (c *composerVersion) exact() *GlobVersion {
+	d := make([]int64, 3)
+	for i, n := range c.parts {
+		if n >= 0 {
+			d[i] = n
+		}
+	}
+	return &GlobVersion{Version: NewVersion(d[0], d[1], d[2], c.preRelease)}
+}
+
+// lowest returns the lowest version matching the version. As Composer appends "-dev" to lower
+// limits without stability, it includes the pre-releases of the version
+func (c *composerVersion) lowest() *GlobVersion {
+	v := c.exact()
+	if v.PreRelease == "" {
+		v.PreRelease = "0"
+	}
+	return v
+}
+
+// bump returns the lowest version, including pre-releases, greater than any version matching
+// the first n components
+func (c *composerVersion) bump(n int) *GlobVersion {
+	d := make([]int64, 3)
+	copy(d, c.parts[:n])
+	d[n-1]++
+	return &GlobVersion{Version: NewVersion(d[0], d[1], d[2], "0")}
+}
+
+// toRange returns the Range containing the versions matched by the version with the operator.
+// to is only used by hyphen ranges
+func (c *composerVersion) toRange(operator string, to *composerVersion) *Range {
+	n := c.concrete()
+	nothing := newGlobVersion(-1, -1, -1)
+	r := &Range{}
+	switch operator {
+	case "", "=", "==", "!=", "<>":
+		switch {
+		case n == 0:
+		case c.wildcard:
+			r.MinVersion, r.AllowMinEquality, r.MaxVersion = c.lowest(), true, c.bump(n)
+		default:
+			r.MinVersion, r.AllowMinEquality, r.MaxVersion, r.AllowMaxEquality = c.exact(), true, c.exact(), true
+		}
+	case "~", "~>":
+		if n == 0 {
+			return r
+		}
+		r.MinVersion, r.AllowMinEquality = c.lowest(), true
+		if n == 3 {
+			r.MaxVersion = c.bump(2)
+		} else {
+			r.MaxVersion = c.bump(1)
+		}
+	case "^":
+		switch {
+		case n == 0:
+			return r
+		case c.parts[0] != 0 || n == 1:
+			r.MaxVersion = c.bump(1)
+		case c.parts[1] != 0 || n == 2:
+			r.MaxVersion = c.bump(2)
+		default:
+			r.MaxVersion = c.bump(3)
+		}
+		r.MinVersion, r.AllowMinEquality = c.lowest(), true
+	case "-":
+		if n > 0 {
+			r.MinVersion, r.AllowMinEquality = c.lowest(), true
+		}
+		switch m := to.concrete(); {
+		case m == 3 || to.preRelease != "":
+			r.MaxVersion, r.AllowMaxEquality = to.exact(), true
+		case m > 0:
+			r.MaxVersion = to.bump(m)
+		}
+	case ">":
+		switch {
+		case n == 0:
+			r.MinVersion = nothing
+		case c.wildcard:
+			r.MinVersion, r.AllowMinEquality = c.bump(n), true
+		default:
+			r.MinVersion = c.exact()
+		}
+	case ">=":
+		if n > 0 {
+			r.MinVersion, r.AllowMinEquality = c.lowest(), true
+		}
+	case "<":
+		if n == 0 {
+			r.MaxVersion = nothing
+		} else {
+			r.MaxVersion = c.lowest()
+		}
+	case "<=":
+		switch {
+		case n == 0:
+		case c.wildcard:
+			r.MaxVersion = c.bump(n)
+		default:
+			r.MaxVersion, r.AllowMaxEquality = c.exact(), true
+		}
+	}
+	return r
+}
+
+// MustParseComposerConstraint parses a Composer version constraint
+// Panics if it cannot be parsed
+func MustParseComposerConstraint(str string) Expression {
+	if e, err := ParseComposerConstraint(str); err != nil {
+		panic(err)
+	} else {
+		return e
+	}
+}
+
+// ParseComposerConstraint parses a version constraint using the syntax of Composer, the PHP
+// package manager, such as "^1.2", "~1.2 || 2.0.*" or ">=1.0 <1.1 || dev-main". It differs
+// from ParseRange and ParseExpr in that:
+//
+//   - Constraints are separated by commas or spaces (AND), and by "|" or "||" (OR). There are no parentheses.
+//   - "~" lets the last specified component go up: "~1.2" is ">=1.2 <2.0.0" (">=1.2.0 <1.3.0" in ParseRange),
+//     while "~1.2.3" is ">=1.2.3 <1.3.0".
+//   - A bare partial version is exact: "1.2" is "=1.2.0", not "1.2.x". Wildcards such as "1.0.*" must be explicit.
+//   - Lower limits without pre-release, such as those of ">=1.2", "^1.2" or "1.0.*", include the pre-releases
+//     of the version, and upper limits such as "<2.0" exclude them: "^1.2" is ">=1.2.0-0 <2.0.0-0".
+//   - Pre-releases are never excluded on their own, as if IncludePrerelease was set. Stability flags such
+//     as "@dev" or "@beta" are accepted and ignored, since they only affect the resolution of dependencies.
+//   - A "-dev" suffix is the lowest pre-release, "1.2-dev" is "1.2.0-0", and "1.0.x-dev" is "1.0.*".
+//   - Branch names, such as "dev-main", are returned as a BranchNode, which does not match any version.
+//
+// Composer orders stabilities as dev < alpha < beta < RC, while pre-releases are compared following
+// the SemVer precedence ("RC1" < "alpha1"). Versions can have a "v" prefix, but at most three components.
+// The returned Expression is an *Expr, whose syntax tree contains a RangeNode for each constraint,
+// with the Composer operator ("-" for hyphen ranges)
+func ParseComposerConstraint(str string) (Expression, error) {
+	p := &composerParser{cargoParser{input: str}}
+	root, err := p.parse()
+	if err != nil {
+		return nil, err
+	}
+	return &Expr{str: str, root: root, parseConfig: parseConfig{includePrerelease: true}}, nil
+}
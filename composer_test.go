@@ -0,0 +1,138 @@
+package semver
+
+import "testing"
+
+// Examples from the Composer documentation ("Versions and constraints")
+var composerConformanceTable = map[string]string{
+	// Exact versions
+	"1.0.2":   "1.0.2",
+	"v1.0.2":  "1.0.2",
+	"1.2":     "1.2.0",
+	"==1.0.2": "1.0.2",
+	// Version ranges
+	">=1.0":                   ">=1.0.0-0",
+	">=1.0 <2.0":              ">=1.0.0-0 <2.0.0-0",
+	">=1.0,<2.0":              ">=1.0.0-0 <2.0.0-0",
+	">=1.0 <1.1 || >=1.2":     ">=1.0.0-0 <1.1.0-0 || >=1.2.0-0",
+	">=1.0 <1.1 | >=1.2":      ">=1.0.0-0 <1.1.0-0 || >=1.2.0-0",
+	"> 1.0":                   ">1.0.0",
+	"<=1.0":                   "<=1.0.0",
+	"!=1.0":                   "<1.0.0 || >1.0.0",
+	"<>1.0.0, >=0.9 <1.1":     ">=0.9.0-0 <1.0.0 || >1.0.0 <1.1.0-0",
+	">=1.2-dev":               ">=1.2.0-0",
+	"1.0 - 2.0":               ">=1.0.0-0 <2.1.0-0",
+	"1.0.0 - 2.1.0":           ">=1.0.0-0 <=2.1.0",
+	"1 - 2":                   ">=1.0.0-0 <3.0.0-0",
+	"1.0.0-rc.1 - 2.0.0-rc.1": ">=1.0.0-rc.1 <=2.0.0-rc.1",
+	// Wildcards
+	"1.0.*": ">=1.0.0-0 <1.1.0-0",
+	"1.*":   ">=1.0.0-0 <2.0.0-0",
+	"1.x":   ">=1.0.0-0 <2.0.0-0",
+	"*":     "*",
+	"<=1.*": "<2.0.0-0",
+	">1.*":  ">=2.0.0-0",
+	// Tilde
+	"~1.2":   ">=1.2.0-0 <2.0.0-0",
+	"~1.2.3": ">=1.2.3-0 <1.3.0-0",
+	"~1":     ">=1.0.0-0 <2.0.0-0",
+	"~>1.2":  ">=1.2.0-0 <2.0.0-0",
+	// Caret
+	"^1.2.3":        ">=1.2.3-0 <2.0.0-0",
+	"^0.3":          ">=0.3.0-0 <0.4.0-0",
+	"^0.0.3":        ">=0.0.3-0 <0.0.4-0",
+	"^0":            ">=0.0.0-0 <1.0.0-0",
+	"^0.0":          ">=0.0.0-0 <0.1.0-0",
+	"^1.2.3-beta.2": ">=1.2.3-beta.2 <2.0.0-0",
+	// Stability flags and branches
+	"1.0.*@beta":           ">=1.0.0-0 <1.1.0-0",
+	"@dev":                 "*",
+	"^2.1@RC":              ">=2.1.0-0 <3.0.0-0",
+	"1.0.x-dev":            ">=1.0.0-0 <1.1.0-0",
+	"dev-main":             "<0.0.0",
+	"dev-main || ^1.2":     ">=1.2.0-0 <2.0.0-0",
+	"^1.0 || ^2.0 || ^3.0": ">=1.0.0-0 <4.0.0-0",
+}
+
+func TestComposerConformance(t *testing.T) {
+	for constraint, expected := range composerConformanceTable {
+		e, err := ParseComposerConstraint(constraint)
+		if err != nil {
+			t.Errorf("Expected %q to be a valid Composer constraint but got %v", constraint, err)
+			continue
+		}
+		if res, err := Canonical(e); err != nil || res != expected {
+			t.Errorf("Expected Composer constraint %q to be %q but got %q (%v)", constraint, expected, res, err)
+		}
+		if e.String() != constraint {
+			t.Errorf("Expected the string of %q to be the original input but got %q", constraint, e)
+		}
+	}
+}
+
+func TestComposerMatches(t *testing.T) {
+	for constraint, data := range map[string]map[string]bool{
+		"^1.2.3":                 {"1.2.3": true, "1.2.3-beta": true, "1.9.0-rc.1": true, "2.0.0-alpha": false, "2.0.0": false},
+		"~1.2":                   {"1.2.0": true, "1.9.9": true, "1.2.0-alpha": true, "1.1.9": false, "2.0.0": false},
+		"1.2.3":                  {"1.2.3": true, "1.2.3+build": true, "1.2.3-rc.1": false, "1.2.4": false},
+		">1.2.3":                 {"1.2.3": false, "1.2.4-alpha": true},
+		"<1.2.3":                 {"1.2.2": true, "1.2.3-alpha": false},
+		"<=1.2.3":                {"1.2.3-alpha": true},
+		"!=1.2.3":                {"1.2.3": false, "1.2.3-alpha": true, "2.0.0-alpha": true},
+		"dev-main":               {"0.0.0": false, "1.0.0": false},
+		"dev-main | 1.0.*":       {"1.0.5": true, "1.1.0": false},
+		"*@dev":                  {"0.0.0-0": true, "1.2.3": true},
+		">=1.2.3-beta.1 <=1.2.3": {"1.2.3-beta.2": true, "1.2.3-alpha": false, "1.2.3": true},
+	} {
+		e := MustParseComposerConstraint(constraint)
+		for v, expected := range data {
+			if e.Matches(MustParseVersion(v)) != expected {
+				t.Errorf("Expected Composer constraint %q to match %q: %v", constraint, v, expected)
+			}
+		}
+	}
+}
+
+func TestComposerAST(t *testing.T) {
+	for constraint, expected := range map[string]string{
+		"^1.2":                 `[^]"^1.2"`,
+		">= 1.0, <2.0":         `(AND [>=]">= 1.0" [<]"<2.0")`,
+		"1.0 - 2.0 || dev-foo": `(OR [-]"1.0 - 2.0" *semver.BranchNode)`,
+		"!=1.5 >1.0 | ~2":      `(OR (AND (NOT [!=]"!=1.5") [>]">1.0") [~]"~2")`,
+	} {
+		if res := dumpAST(constraint, MustParseComposerConstraint(constraint).(*Expr).AST()); res != expected {
+			t.Errorf("Expected the AST of Composer constraint %q to be %s but got %s", constraint, expected, res)
+		}
+	}
+	var branch *BranchNode
+	Inspect(MustParseComposerConstraint("^1.0 || dev-feature/foo").(*Expr).AST(), func(n Node) bool {
+		if b, ok := n.(*BranchNode); ok {
+			branch = b
+		}
+		return true
+	})
+	if branch == nil || branch.Name != "feature/foo" || branch.Location != (Span{Start: 8, End: 23}) {
+		t.Errorf("Expected a BranchNode for branch %q but got %+v", "feature/foo", branch)
+	}
+}
+
+func TestComposerErrors(t *testing.T) {
+	for constraint, offset := range map[string]int{
+		"":              0,
+		"1.2 ||":        6,
+		"1.2,":          4,
+		"1.2.3.4":       5,
+		"1.2.3-":        6,
+		"1.*.3":         4,
+		"1.*-beta":      4,
+		"1.0@foo":       4,
+		"(1.0)":         0,
+		">=1.0 && <2.0": 6,
+		"dev-":          4,
+		"1.2.3-beta..1": 11,
+	} {
+		_, err := ParseComposerConstraint(constraint)
+		if pErr, ok := err.(*ParseError); !ok || pErr.Offset != offset {
+			t.Errorf("Expected Composer constraint %q to fail at offset %d but got %v", constraint, offset, err)
+		}
+	}
+}
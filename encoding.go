@@ -0,0 +1,229 @@
+package semver
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// includePrereleasePrefix precedes the text form of ranges and expressions parsed with
+// IncludePrerelease, such as "includePrerelease:^1.2". It cannot start a valid expression
+const includePrereleasePrefix = "includePrerelease:"
+
+// marshalTextWithConfig returns the text form of a range or expression written as str
+func marshalTextWithConfig(str string, includePrerelease bool) []byte {
+	if includePrerelease {
+		str = includePrereleasePrefix + str
+	}
+	return []byte(str)
+}
+
+// unmarshalTextWithConfig parses the text form of a range or expression with parse, passing the
+// options described by its prefix. Offsets of syntax errors refer to the whole text
+func unmarshalTextWithConfig(text []byte, parse func(str string, opts ...ParseOption) error) error {
+	str := string(text)
+	if !strings.HasPrefix(str, includePrereleasePrefix) {
+		return parse(str)
+	}
+	err := parse(str[len(includePrereleasePrefix):], IncludePrerelease)
+	if pErr, ok := err.(*ParseError); ok {
+		return &ParseError{Input: str, Offset: pErr.Offset + len(includePrereleasePrefix), Token: pErr.Token, Expected: pErr.Expected}
+	}
+	return err
+}
+
+// unmarshalJSONString decodes a JSON string and passes it to unmarshalText. JSON null values are ignored
+func unmarshalJSONString(data []byte, unmarshalText func(text []byte) error) error {
+	if string(data) == "null" {
+		return nil
+	}
+	var str string
+	if err := json.Unmarshal(data, &str); err != nil {
+		return err
+	}
+	return unmarshalText([]byte(str))
+}
+
+// MarshalText implements encoding.TextMarshaler
+func (v *Version) MarshalText() ([]byte, error) {
+	return []byte(v.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler. Syntax errors are reported as a *ParseError
+func (v *Version) UnmarshalText(text []byte) error {
+	parsed, err := ParseVersion(string(text))
+	if err != nil {
+		return versionParseError(string(text))
+	}
+	*v = *parsed
+	return nil
+}
+
+// versionParseError returns a *ParseError locating the token that prevents str from being a version
+func versionParseError(str string) error {
+	p, err := newParser(str)
+	if err != nil {
+		return err
+	}
+	t := p.next()
+	if t.typ != tokenVersion {
+		return p.errorAt(t, "version")
+	}
+	if err := p.expectEOF("end of version"); err != nil {
+		return err
+	}
+	return p.errorAt(t, "version")
+}
+
+// UnmarshalJSON allows deserializing a Version from a string
+func (v *Version) UnmarshalJSON(data []byte) error {
+	return unmarshalJSONString(data, v.UnmarshalText)
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler. The binary form is the version string
+func (v *Version) MarshalBinary() ([]byte, error) {
+	return v.MarshalText()
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler
+func (v *Version) UnmarshalBinary(data []byte) error {
+	return v.UnmarshalText(data)
+}
+
+// MarshalText implements encoding.TextMarshaler
+func (v *GlobVersion) MarshalText() ([]byte, error) {
+	return []byte(v.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler. Syntax errors are reported as a *ParseError
+func (v *GlobVersion) UnmarshalText(text []byte) error {
+	p, err := newParser(string(text))
+	if err != nil {
+		return err
+	}
+	parsed, err := p.parseVersion()
+	if err != nil {
+		return err
+	}
+	if err := p.expectEOF("end of version"); err != nil {
+		return err
+	}
+	*v = *parsed
+	return nil
+}
+
+// MarshalJSON allows serializing the GlobVersion as a string
+func (v *GlobVersion) MarshalJSON() ([]byte, error) {
+	return json.Marshal(v.String())
+}
+
+// UnmarshalJSON allows deserializing a GlobVersion from a string
+func (v *GlobVersion) UnmarshalJSON(data []byte) error {
+	return unmarshalJSONString(data, v.UnmarshalText)
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler. The binary form is the x-range string
+func (v *GlobVersion) MarshalBinary() ([]byte, error) {
+	return v.MarshalText()
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler
+func (v *GlobVersion) UnmarshalBinary(data []byte) error {
+	return v.UnmarshalText(data)
+}
+
+// MarshalText implements encoding.TextMarshaler. Ranges are serialized in their canonical form,
+// preceded by "includePrerelease:" if IncludePrerelease is set
+func (r Range) MarshalText() ([]byte, error) {
+	return marshalTextWithConfig(r.String(), r.IncludePrerelease), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler. Besides the syntax supported by ParseRange,
+// it accepts any expression describing a single interval, such as the canonical ">=1.2.0 <1.3.0".
+// Syntax errors are reported as a *ParseError
+func (r *Range) UnmarshalText(text []byte) error {
+	return unmarshalTextWithConfig(text, func(str string, opts ...ParseOption) error {
+		root, err := ParseAST(str, opts...)
+		if err != nil {
+			return err
+		}
+		if n, ok := root.(*RangeNode); ok {
+			*r = *n.Range
+			return nil
+		}
+		s := nodeSet(root)
+		if len(opts) > 0 {
+			s = exactSetOf(root, true).releases
+		}
+		switch len(s) {
+		case 0:
+			*r = *MustParseRange("<*")
+		case 1:
+			*r = *s[0].toRange()
+		default:
+			return fmt.Errorf("expression %q does not describe a single range", text)
+		}
+		r.IncludePrerelease = len(opts) > 0
+		return nil
+	})
+}
+
+// MarshalJSON allows serializing the Range as a string
+func (r Range) MarshalJSON() ([]byte, error) {
+	text, _ := r.MarshalText()
+	return json.Marshal(string(text))
+}
+
+// UnmarshalJSON allows deserializing a Range from a string
+func (r *Range) UnmarshalJSON(data []byte) error {
+	return unmarshalJSONString(data, r.UnmarshalText)
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler. The binary form is the text form
+func (r Range) MarshalBinary() ([]byte, error) {
+	return r.MarshalText()
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler
+func (r *Range) UnmarshalBinary(data []byte) error {
+	return r.UnmarshalText(data)
+}
+
+// MarshalText implements encoding.TextMarshaler. Expressions are serialized as they were written,
+// preceded by "includePrerelease:" if they were parsed with IncludePrerelease
+func (e Expr) MarshalText() ([]byte, error) {
+	return marshalTextWithConfig(e.String(), e.includePrerelease), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler. Syntax errors are reported as a *ParseError
+func (e *Expr) UnmarshalText(text []byte) error {
+	return unmarshalTextWithConfig(text, func(str string, opts ...ParseOption) error {
+		parsed, err := ParseExpr(str, opts...)
+		if err != nil {
+			return err
+		}
+		*e = *parsed.(*Expr)
+		return nil
+	})
+}
+
+// MarshalJSON allows serializing the Expr as a string
+func (e Expr) MarshalJSON() ([]byte, error) {
+	text, _ := e.MarshalText()
+	return json.Marshal(string(text))
+}
+
+// UnmarshalJSON allows deserializing an Expr from a string
+func (e *Expr) UnmarshalJSON(data []byte) error {
+	return unmarshalJSONString(data, e.UnmarshalText)
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler. The binary form is the text form
+func (e Expr) MarshalBinary() ([]byte, error) {
+	return e.MarshalText()
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler
+func (e *Expr) UnmarshalBinary(data []byte) error {
+	return e.UnmarshalText(data)
+}
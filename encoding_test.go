@@ -0,0 +1,200 @@
+package semver
+
+import (
+	"encoding"
+	"encoding/json"
+	"testing"
+)
+
+type config struct {
+	Version    *Version     `json:"version"`
+	Glob       *GlobVersion `json:"glob"`
+	Range      *Range       `json:"range"`
+	Constraint Expr         `json:"constraint"`
+}
+
+func TestJSONRoundTrip(t *testing.T) {
+	data := `{"version":"1.2.3-rc.1+b.2","glob":"1.2.x","range":"~1.2","constraint":"^1.2 || >=3.0.0-rc.1 <4"}`
+	cfg := &config{}
+	if err := json.Unmarshal([]byte(data), cfg); err != nil {
+		t.Fatalf("Expected %s to be decoded but got %v", data, err)
+	}
+	if cfg.Version.String() != "1.2.3-rc.1+b.2" || cfg.Glob.String() != "1.2.x" ||
+		cfg.Range.String() != ">=1.2.0 <1.3.0" || cfg.Constraint.String() != "^1.2 || >=3.0.0-rc.1 <4" {
+		t.Errorf("Unexpected decoded values %+v", cfg)
+	}
+	if !cfg.Constraint.Matches(MustParseVersion("3.0.0-rc.2")) || cfg.Constraint.Matches(MustParseVersion("2.0.0")) {
+		t.Errorf("Expected the decoded expression to be usable")
+	}
+	encoded, err := json.Marshal(cfg)
+	if err != nil {
+		t.Fatalf("Expected %+v to be encoded but got %v", cfg, err)
+	}
+	// encoding/json escapes "<" and ">"
+	expected := `{"version":"1.2.3-rc.1+b.2","glob":"1.2.x","range":"\u003e=1.2.0 \u003c1.3.0","constraint":"^1.2 || \u003e=3.0.0-rc.1 \u003c4"}`
+	if string(encoded) != expected {
+		t.Errorf("Expected %+v to be encoded as %s but got %s", cfg, expected, encoded)
+	}
+	decoded := &config{}
+	if err := json.Unmarshal(encoded, decoded); err != nil {
+		t.Fatalf("Expected %s to be decoded but got %v", encoded, err)
+	}
+	rangeOk, _ := Equivalent(decoded.Range, cfg.Range)
+	constraintOk, _ := Equivalent(&decoded.Constraint, &cfg.Constraint)
+	if Compare(decoded.Version, cfg.Version) != 0 || decoded.Glob.String() != cfg.Glob.String() || !rangeOk || !constraintOk {
+		t.Errorf("Expected %+v to be equal to %+v", decoded, cfg)
+	}
+}
+
+func TestJSONNull(t *testing.T) {
+	cfg := &config{}
+	if err := json.Unmarshal([]byte(`{"version":null,"glob":null,"range":null,"constraint":null}`), cfg); err != nil {
+		t.Fatalf("Expected null values to be accepted but got %v", err)
+	}
+	if cfg.Version != nil || cfg.Range != nil || cfg.Glob != nil || cfg.Constraint.String() != "" {
+		t.Errorf("Expected null values to be ignored but got %+v", cfg)
+	}
+	if !cfg.Constraint.Matches(MustParseVersion("1.0.0")) {
+		t.Errorf("Expected the zero value of Expr to match any version")
+	}
+}
+
+func TestJSONErrors(t *testing.T) {
+	for data, offset := range map[string]int{
+		`{"constraint":">=1.2.3 <2.0 foo"}`:          13,
+		`{"range":"1.2.3 - - 2"}`:                    8,
+		`{"glob":"1.2.x.y"}`:                         5,
+		`{"version":"1.2.x"}`:                        0,
+		`{"version":"1.2.3 <2"}`:                     6,
+		`{"version":""}`:                             0,
+		`{"constraint":"includePrerelease:^1.2 ||"}`: 25,
+	} {
+		err := json.Unmarshal([]byte(data), &config{})
+		if pErr, ok := err.(*ParseError); !ok || pErr.Offset != offset {
+			t.Errorf("Expected decoding %s to fail with a *ParseError at offset %d but got %v", data, offset, err)
+		}
+	}
+	for _, data := range []string{`{"range":"1.x || 3.x"}`, `{"version":3}`} {
+		if err := json.Unmarshal([]byte(data), &config{}); err == nil {
+			t.Errorf("Expected decoding %s to fail", data)
+		}
+	}
+}
+
+func TestTextAndBinaryRoundTrip(t *testing.T) {
+	for _, test := range []struct {
+		value    interface{}
+		empty    interface{}
+		text     string
+		expected string
+	}{
+		{value: MustParseVersion("v1.2.3-rc.1"), empty: &Version{}, text: "1.2.3-rc.1"},
+		{value: MustParseGlobVersion("1.2"), empty: &GlobVersion{}, text: "1.2.x"},
+		{value: MustParseGlobVersion("*"), empty: &GlobVersion{}, text: "x.x.x"},
+		{value: MustParseRange("^1.2.3"), empty: &Range{}, text: ">=1.2.3 <2.0.0"},
+		{value: MustParseRange("<*"), empty: &Range{}, text: "<0.0.0"},
+		{value: MustParseExpr("1.x || ^3"), empty: &Expr{}, text: "1.x || ^3"},
+		{value: MustParseRange("^1.2.3", IncludePrerelease), empty: &Range{}, text: "includePrerelease:>=1.2.3 <2.0.0-0"},
+		{value: MustParseExpr("1.x || ^3", IncludePrerelease), empty: &Expr{}, text: "includePrerelease:1.x || ^3"},
+		{value: MustParseRange("<*", IncludePrerelease), empty: &Range{}, text: "includePrerelease:<0.0.0-0"},
+		{value: *MustParseRange("~1.2"), empty: &Range{}, text: ">=1.2.0 <1.3.0"},
+		{value: *MustParseExpr("1.x || ^3").(*Expr), empty: &Expr{}, text: "1.x || ^3"},
+	} {
+		for _, codec := range []struct {
+			marshal   func() ([]byte, error)
+			unmarshal func([]byte) error
+		}{
+			{test.value.(encoding.TextMarshaler).MarshalText, test.empty.(encoding.TextUnmarshaler).UnmarshalText},
+			{test.value.(encoding.BinaryMarshaler).MarshalBinary, test.empty.(encoding.BinaryUnmarshaler).UnmarshalBinary},
+		} {
+			data, err := codec.marshal()
+			if err != nil || string(data) != test.text {
+				t.Errorf("Expected %v to be serialized as %q but got %q (%v)", test.value, test.text, data, err)
+			}
+			if err := codec.unmarshal(data); err != nil {
+				t.Errorf("Expected %q to be deserialized but got %v", data, err)
+			}
+			if again, _ := test.empty.(encoding.TextMarshaler).MarshalText(); string(again) != test.text {
+				t.Errorf("Expected %q to be deserialized as %q but got %q", data, test.text, again)
+			}
+		}
+	}
+}
+
+func TestRangeUnmarshalCanonicalForms(t *testing.T) {
+	for _, str := range []string{">=1.2.0 <1.3.0", ">1.2.3 <=2.0.0", "*", "<0.0.0", "1.2.3", ">=1.0.0 >=1.2.0 <3.0.0 || 1.5.x"} {
+		r := &Range{}
+		if err := r.UnmarshalText([]byte(str)); err != nil {
+			t.Errorf("Expected %q to be deserialized as a range but got %v", str, err)
+			continue
+		}
+		if ok, err := Equivalent(r, MustParseExpr(str)); err != nil || !ok {
+			t.Errorf("Expected %q to be deserialized as an equivalent range but got %q", str, r)
+		}
+	}
+}
+
+func TestIncludePrereleaseRoundTrip(t *testing.T) {
+	v := MustParseVersion("1.5.0-beta.1")
+	cfg := &config{Range: MustParseRange("^1.2", IncludePrerelease), Constraint: *MustParseExpr("^1.2 || ^3", IncludePrerelease).(*Expr)}
+	encoded, err := json.Marshal(cfg)
+	if err != nil {
+		t.Fatalf("Expected %+v to be encoded but got %v", cfg, err)
+	}
+	decoded := &config{}
+	if err := json.Unmarshal(encoded, decoded); err != nil {
+		t.Fatalf("Expected %s to be decoded but got %v", encoded, err)
+	}
+	if !decoded.Range.Matches(v) || !decoded.Constraint.Matches(v) {
+		t.Errorf("Expected %s to be decoded including pre-releases", encoded)
+	}
+	data, _ := cfg.Range.MarshalBinary()
+	if r := (&Range{}); r.UnmarshalBinary(data) != nil || !r.Matches(v) {
+		t.Errorf("Expected the range %q to be deserialized including pre-releases", data)
+	}
+	data, _ = cfg.Constraint.MarshalBinary()
+	if e := (&Expr{}); e.UnmarshalBinary(data) != nil || !e.Matches(v) {
+		t.Errorf("Expected the expression %q to be deserialized including pre-releases", data)
+	}
+	// Canonical ranges describing a single interval keep the flag too
+	r := &Range{}
+	if err := r.UnmarshalText([]byte("includePrerelease:>=1.0.0 >=1.2.0 <2.0.0")); err != nil || !r.Matches(v) {
+		t.Errorf("Expected the canonical range to be deserialized including pre-releases but got %q (%v)", r, err)
+	}
+}
+
+func TestRangeRoundTripKeepsContains(t *testing.T) {
+	for _, test := range []struct {
+		battery map[string]map[string]bool
+		opts    []ParseOption
+	}{
+		{battery: preReleaseTestBattery},
+		{battery: includePreReleaseTestBattery, opts: []ParseOption{IncludePrerelease}},
+	} {
+		for rangeStr, data := range test.battery {
+			text, _ := MustParseRange(rangeStr, test.opts...).MarshalText()
+			r := &Range{}
+			if err := r.UnmarshalText(text); err != nil {
+				t.Errorf("Expected %q to be deserialized but got %v", text, err)
+				continue
+			}
+			for v, result := range data {
+				if r.Contains(MustParseVersion(v)) != result {
+					t.Errorf("Expected %q (serialized %v) to evaluate %v to %v", text, rangeStr, v, result)
+				}
+			}
+		}
+	}
+}
+
+func TestMarshalValues(t *testing.T) {
+	values := struct {
+		Range      Range `json:"range"`
+		Constraint Expr  `json:"constraint"`
+	}{*MustParseRange("^1.2", IncludePrerelease), *MustParseExpr("1.x || ^3").(*Expr)}
+	// encoding/json escapes "<" and ">"
+	expected := `{"range":"includePrerelease:\u003e=1.2.0-0 \u003c2.0.0-0","constraint":"1.x || ^3"}`
+	if encoded, err := json.Marshal(values); err != nil || string(encoded) != expected {
+		t.Errorf("Expected %+v to be encoded as %s but got %s (%v)", values, expected, encoded, err)
+	}
+}
@@ -1,18 +1,9 @@
 package semver
 
-import (
-	"fmt"
-	"regexp"
-	"strings"
-)
-
-var expressionRe = regexp.MustCompile(
-	`(` + rangeExpr.String() + `)\s*` +
-		`(?P<union>(\|\||\s*))\s*(?P<rest>.*)`)
-
 type evaluable interface {
-	// evaluate checks if the provided version v is matches the expression
-	evaluate(v *Version) bool
+	// evaluate checks if the provided version v is matches the expression. If preReleaseAllowed
+	// is false, pre-release versions must also be explicitly allowed by a range
+	evaluate(v *Version, preReleaseAllowed bool) bool
 }
 
 // Expression defines a semver expression
@@ -21,44 +12,35 @@ type Expression interface {
 	String() string
 }
 
-type semverExpression struct {
-	str string
-	c   evaluable
+// Expr is the Expression implementation returned by ParseExpr. Its zero value is
+// equivalent to an empty expression, which matches any version
+type Expr struct {
+	str  string
+	root Node
+	parseConfig
 }
 
-func (e *semverExpression) String() string {
+func (e *Expr) String() string {
 	return e.str
 }
 
-// Matches checks if the provided version v is accepted by the expression
-func (e *semverExpression) Matches(v *Version) bool {
-	return e.c.evaluate(v)
-}
-
-type exprCondition struct {
-	Op        string
-	Operator1 evaluable
-	Operator2 evaluable
-}
-
-type trueCondition struct {
-}
-
-func (c *trueCondition) evaluate(v *Version) bool {
-	return true
+// AST returns the syntax tree of the expression
+func (e *Expr) AST() Node {
+	if e.root == nil {
+		return &AndNode{}
+	}
+	return e.root
 }
 
-func (c *exprCondition) evaluate(v *Version) bool {
-	if c.Op == "AND" {
-		return c.Operator1.evaluate(v) && c.Operator2.evaluate(v)
-	}
-	return c.Operator1.evaluate(v) || c.Operator2.evaluate(v)
+// Matches checks if the provided version v is accepted by the expression
+func (e *Expr) Matches(v *Version) bool {
+	return e.AST().evaluate(v, e.includePrerelease)
 }
 
 // MustParseExpr parses a semver string
 // It returns the expression if str is well formed and panics otherwise
-func MustParseExpr(str string) (expr Expression) {
-	expr, err := ParseExpr(str)
+func MustParseExpr(str string, opts ...ParseOption) (expr Expression) {
+	expr, err := ParseExpr(str, opts...)
 	if err != nil {
 		panic(err)
 	}
@@ -66,35 +48,18 @@ func MustParseExpr(str string) (expr Expression) {
 }
 
 // ParseExpr parses a semver string
-// It returns the expression if str is well formed and a non-nil error otherwise
-func ParseExpr(str string) (Expression, error) {
-	text := str
-	var condition evaluable
-	condition = &trueCondition{}
-	op := "AND"
-	for {
-		matched, mapping := namedReEvaluate(expressionRe, text)
-		if !matched {
-			break
-		}
-		text = mapping["rest"]
-		var ev evaluable
-		switch {
-		case mapping["range"] != "":
-			ev = MustParseRange(mapping["range"])
-		}
-
-		condition = &exprCondition{Op: op, Operator1: condition, Operator2: ev}
-		if mapping["union"] == `||` {
-			op = "OR"
-		} else {
-			op = "AND"
-		}
+// It returns the expression, an *Expr, if str is well formed and a non-nil error otherwise.
+// Syntax errors are reported as a *ParseError. Ranges are parsed as in ParseRange, so "~>" is
+// the npm alias of "~" and not the pessimistic operator of RubyGems
+func ParseExpr(str string, opts ...ParseOption) (Expression, error) {
+	e := &Expr{str: str}
+	for _, opt := range opts {
+		opt(&e.parseConfig)
 	}
-	if condition == nil {
-		return nil, fmt.Errorf(`Cannot parse expression %q`, str)
-	} else if strings.TrimSpace(text) != "" {
-		return &semverExpression{c: condition, str: str}, fmt.Errorf(`Extra characters found in expression: %q`, text)
+	root, err := ParseAST(str, opts...)
+	if err != nil {
+		return nil, err
 	}
-	return &semverExpression{c: condition, str: str}, nil
+	e.root = root
+	return e, nil
 }
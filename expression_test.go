@@ -29,6 +29,112 @@ var exprTestBattery = map[string]map[string]bool{
 		"1.2.8": false,
 		"2.0.0": false,
 	},
+	"(>=1.2 <2.0) || (>=3.0 <4.0)": {
+		"1.2.0": true,
+		"1.9.9": true,
+		"3.0.0": true,
+		"3.5.1": true,
+		"2.0.0": false,
+		"2.5.0": false,
+		"4.0.0": false,
+		"1.1.0": false,
+	},
+	">=1.2 && <2.0": {
+		"1.2.0": true,
+		"1.9.9": true,
+		"2.0.0": false,
+		"1.1.0": false,
+	},
+	"1.x || 2.x && <2.5": {
+		"1.0.0": true,
+		"1.9.0": true,
+		"2.4.9": true,
+		"2.5.0": false,
+		"3.0.0": false,
+	},
+	"2.x <2.5 || 1.x": {
+		"1.0.0": true,
+		"2.4.9": true,
+		"2.5.0": false,
+	},
+	"^1.4.0 !1.4.2": {
+		"1.4.0": true,
+		"1.4.1": true,
+		"1.4.2": false,
+		"1.4.3": true,
+		"2.0.0": false,
+	},
+	"^1.4.0 != 1.4.2 !=1.5.x": {
+		"1.4.1": true,
+		"1.4.2": false,
+		"1.5.0": false,
+		"1.5.9": false,
+		"1.6.0": true,
+	},
+	"!(1.x || 3.x)": {
+		"0.9.0": true,
+		"1.2.3": false,
+		"2.0.0": true,
+		"3.9.0": false,
+		"4.0.0": true,
+	},
+	"!!1.2.3": {
+		"1.2.3": true,
+		"1.2.4": false,
+	},
+	"((1.x) && (!1.2.x || 1.2.3))": {
+		"1.1.0": true,
+		"1.2.0": false,
+		"1.2.3": true,
+		"2.0.0": false,
+	},
+}
+
+var exprPreReleaseTestBattery = map[string]map[string]bool{
+	">=1.2.3-beta.2 <2.0.0": {
+		"1.2.3-beta.5": true,
+		"1.2.3-beta.1": false,
+		"1.5.0-alpha":  false,
+		"1.5.0":        true,
+	},
+	"<1.0.0 || >=1.2.3-beta.2 <2.0.0": {
+		"1.2.3-beta.5": true,
+		"0.9.0-beta":   false,
+	},
+	"(>=1.2.3-beta.2) (<2.0.0 || 3.x)": {
+		"1.2.3-beta.5": true,
+		"1.5.0-alpha":  false,
+	},
+	"^1.2.3-beta.1 !1.2.3-beta.3": {
+		"1.2.3-beta.2": true,
+		"1.2.3-beta.3": false,
+		"1.2.4-beta.3": false,
+	},
+	"!1.2.3": {
+		"1.2.4-beta": false,
+		"1.2.4":      true,
+	},
+	"": {
+		"1.2.4-beta": false,
+		"1.2.4":      true,
+	},
+}
+
+func TestExprPreReleases(t *testing.T) {
+	for exprStr, data := range exprPreReleaseTestBattery {
+		e := MustParseExpr(exprStr)
+		ei := MustParseExpr(exprStr, IncludePrerelease)
+		for vStr, result := range data {
+			v := MustParseVersion(vStr)
+			if e.Matches(v) != result {
+				t.Errorf("Expected %q of %v to evaluate to %v", exprStr, v, result)
+			}
+			// Including pre-releases, the expression is a plain set of versions
+			if ei.Matches(v) != nodeSet(ei.(*Expr).AST()).contains(v) {
+				t.Errorf("Expected %q of %v to evaluate to %v when including pre-releases", exprStr, v, !ei.Matches(v))
+			}
+		}
+	}
 }
 
 func TestPerseExpr(t *testing.T) {
@@ -0,0 +1,111 @@
+package semver
+
+import "fmt"
+
+// FilterOption configures how FilterSatisfying, MaxSatisfying and MinSatisfying process a list of versions
+type FilterOption func(c *filterConfig)
+
+type filterConfig struct {
+	excludePreReleases bool
+	skipInvalid        bool
+	report             *[]error
+}
+
+// ExcludePreReleases makes the filtering functions ignore any pre-release version, even if
+// it satisfies the expression
+var ExcludePreReleases FilterOption = func(c *filterConfig) {
+	c.excludePreReleases = true
+}
+
+// SkipInvalid makes the filtering functions ignore the version strings that cannot be parsed
+// instead of failing. The parsing errors are appended to report, if not nil
+func SkipInvalid(report *[]error) FilterOption {
+	return func(c *filterConfig) {
+		c.skipInvalid = true
+		c.report = report
+	}
+}
+
+func toVersionList(versions interface{}, c *filterConfig) ([]*Version, error) {
+	switch list := versions.(type) {
+	case []*Version:
+		return list, nil
+	case []string:
+		result := make([]*Version, 0, len(list))
+		for _, str := range list {
+			v, err := ParseVersion(str)
+			if err == nil {
+				result = append(result, v)
+				continue
+			}
+			if !c.skipInvalid {
+				return nil, fmt.Errorf("Cannot parse version: %v", err)
+			}
+			if c.report != nil {
+				*c.report = append(*c.report, err)
+			}
+		}
+		return result, nil
+	default:
+		return nil, fmt.Errorf("unknown element type: %T", list)
+	}
+}
+
+// FilterSatisfying receives a list of versions ([]*Version or []string) and an expression (Expression
+// or its string representation) and returns, in the same order, the versions satisfying the expression
+func FilterSatisfying(versions interface{}, expr interface{}, opts ...FilterOption) ([]*Version, error) {
+	c := &filterConfig{}
+	for _, opt := range opts {
+		opt(c)
+	}
+	e, err := toExpression(expr)
+	if err != nil {
+		return nil, fmt.Errorf("Cannot parse expression: %v", err)
+	}
+	list, err := toVersionList(versions, c)
+	if err != nil {
+		return nil, err
+	}
+	result := []*Version{}
+	for _, v := range list {
+		if v == nil || (c.excludePreReleases && v.PreRelease != "") {
+			continue
+		}
+		if e.Matches(v) {
+			result = append(result, v)
+		}
+	}
+	return result, nil
+}
+
+// MaxSatisfying returns the highest version in the list satisfying the expression, or nil
+// if none does. It accepts the same arguments as FilterSatisfying
+func MaxSatisfying(versions interface{}, expr interface{}, opts ...FilterOption) (*Version, error) {
+	list, err := FilterSatisfying(versions, expr, opts...)
+	if err != nil {
+		return nil, err
+	}
+	var max *Version
+	for _, v := range list {
+		if max == nil || v.Greater(max) {
+			max = v
+		}
+	}
+	return max, nil
+}
+
+// MinSatisfying returns the lowest version in the list satisfying the expression, or nil
+// if none does. It accepts the same arguments as FilterSatisfying
+func MinSatisfying(versions interface{}, expr interface{}, opts ...FilterOption) (*Version, error) {
+	list, err := FilterSatisfying(versions, expr, opts...)
+	if err != nil {
+		return nil, err
+	}
+	var min *Version
+	for _, v := range list {
+		if min == nil || v.Less(min) {
+			min = v
+		}
+	}
+	return min, nil
+}
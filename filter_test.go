@@ -0,0 +1,92 @@
+package semver
+
+import (
+	"fmt"
+	"testing"
+)
+
+var availableVersions = []string{"1.2.3", "1.2.4", "1.3.0", "2.0.0-rc.1", "1.4.0-beta", "2.0.0", "2.1.3", "0.9.0"}
+
+type filterTest struct {
+	expr     string
+	opts     []FilterOption
+	filtered []string
+	max      string
+	min      string
+}
+
+var filterTestBattery = []filterTest{
+	{expr: "^1.2.3", filtered: []string{"1.2.3", "1.2.4", "1.3.0"}, max: "1.3.0", min: "1.2.3"},
+	{expr: ">=1.4.0-alpha <3", filtered: []string{"1.4.0-beta", "2.0.0", "2.1.3"}, max: "2.1.3", min: "1.4.0-beta"},
+	{expr: "~1.2.3 || >=2.1", filtered: []string{"1.2.3", "1.2.4", "2.1.3"}, max: "2.1.3", min: "1.2.3"},
+	{expr: ">=1.4.0-alpha", opts: []FilterOption{ExcludePreReleases}, filtered: []string{"2.0.0", "2.1.3"}, max: "2.1.3", min: "2.0.0"},
+	{expr: "<1.0.0", filtered: []string{"0.9.0"}, max: "0.9.0", min: "0.9.0"},
+	{expr: ">3", filtered: []string{}, max: "", min: ""},
+}
+
+func TestFilterSatisfying(t *testing.T) {
+	objs := []*Version{}
+	for _, str := range availableVersions {
+		objs = append(objs, MustParseVersion(str))
+	}
+	for _, test := range filterTestBattery {
+		for _, versions := range []interface{}{availableVersions, objs} {
+			for _, expr := range []interface{}{test.expr, MustParseExpr(test.expr)} {
+				list, err := FilterSatisfying(versions, expr, test.opts...)
+				if err != nil {
+					t.Errorf("Expected FilterSatisfying(%q) to succeed but got %v", test.expr, err)
+				} else if fmt.Sprint(list) != fmt.Sprint(test.filtered) {
+					t.Errorf("Expected FilterSatisfying(%q) to be %v but got %v", test.expr, test.filtered, list)
+				}
+				max, err := MaxSatisfying(versions, expr, test.opts...)
+				if err != nil || max.String() != test.max {
+					t.Errorf("Expected MaxSatisfying(%q) to be %q but got %q (%v)", test.expr, test.max, max, err)
+				}
+				min, err := MinSatisfying(versions, expr, test.opts...)
+				if err != nil || min.String() != test.min {
+					t.Errorf("Expected MinSatisfying(%q) to be %q but got %q (%v)", test.expr, test.min, min, err)
+				}
+			}
+		}
+	}
+}
+
+func TestFilterSatisfyingIncludingPreReleases(t *testing.T) {
+	e := MustParseExpr("^1.2.3", IncludePrerelease)
+	list, err := FilterSatisfying(availableVersions, e)
+	if expected := "[1.2.3 1.2.4 1.3.0 1.4.0-beta]"; err != nil || fmt.Sprint(list) != expected {
+		t.Errorf("Expected FilterSatisfying to be %v but got %v (%v)", expected, list, err)
+	}
+	max, err := MaxSatisfying(availableVersions, e)
+	if err != nil || max.String() != "1.4.0-beta" {
+		t.Errorf("Expected MaxSatisfying to be 1.4.0-beta but got %v (%v)", max, err)
+	}
+	max, err = MaxSatisfying(availableVersions, e, ExcludePreReleases)
+	if err != nil || max.String() != "1.3.0" {
+		t.Errorf("Expected MaxSatisfying to be 1.3.0 but got %v (%v)", max, err)
+	}
+}
+
+func TestFilterSatisfyingInvalidInput(t *testing.T) {
+	versions := []string{"1.2.3", "foo", "1.5.0", "1.2.3.4"}
+	if _, err := MaxSatisfying(versions, "^1.0.0"); err == nil {
+		t.Errorf("Expected MaxSatisfying to fail with unparseable versions")
+	}
+	report := []error{}
+	max, err := MaxSatisfying(versions, "^1.0.0", SkipInvalid(&report))
+	if err != nil || max.String() != "1.5.0" {
+		t.Errorf("Expected MaxSatisfying to skip invalid versions and return 1.5.0 but got %v (%v)", max, err)
+	}
+	if len(report) != 2 {
+		t.Errorf("Expected 2 invalid versions to be reported but got %v", report)
+	}
+	if _, err := MaxSatisfying(versions, "^1.0.0", SkipInvalid(nil)); err != nil {
+		t.Errorf("Expected MaxSatisfying to skip invalid versions without report but got %v", err)
+	}
+	if _, err := FilterSatisfying(versions, "foo"); err == nil {
+		t.Errorf("Expected FilterSatisfying to fail with an invalid expression")
+	}
+	if _, err := FilterSatisfying([]int{1, 2}, "*"); err == nil {
+		t.Errorf("Expected FilterSatisfying to fail with an unsupported list type")
+	}
+}
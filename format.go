@@ -0,0 +1,91 @@
+package semver
+
+import (
+	"fmt"
+	"strings"
+)
+
+// String returns the canonical comparator form of the range, such as ">=1.2.0 <1.3.0" for "~1.2".
+// With IncludePrerelease, the form describes exactly the versions it contains, pre-releases included
+func (r *Range) String() string {
+	if r.IncludePrerelease {
+		return r.exactVersionSet().format(lowestPreRelease())
+	}
+	return r.versionSet().String()
+}
+
+// Canonical returns the normalized comparator form of the expression: a "||" separated
+// list of disjoint intervals, sorted in ascending order. Equivalent expressions, such as
+// "~1.2" and ">=1.2.0 <1.3.0", have the same canonical form. It supports expressions returned by
+// ParseExpr and ranges, and fails with any other type
+func Canonical(e Expression) (string, error) {
+	s, err := setOf(e)
+	if err != nil {
+		return "", err
+	}
+	return s.String(), nil
+}
+
+// Shortest returns the canonical form of the expression, using for each interval the
+// shortest equivalent among the comparator, caret, tilde, x-range and hyphen forms
+func Shortest(e Expression) (string, error) {
+	s, err := setOf(e)
+	if err != nil || len(s) == 0 {
+		return s.String(), err
+	}
+	list := []string{}
+	for _, i := range s {
+		list = append(list, i.shortest())
+	}
+	return strings.Join(list, " || "), nil
+}
+
+func (s versionSet) equal(s2 versionSet) bool {
+	if len(s) != len(s2) {
+		return false
+	}
+	for idx, i := range s {
+		if compareLower(i.min, s2[idx].min) != 0 || compareUpper(i.max, s2[idx].max) != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// sugarCandidates returns the short forms that may describe the interval. Intervals
+// without lower limit are always printed as comparators
+func (i interval) sugarCandidates() []string {
+	if i.max.v == nil || !i.min.inclusive || i.isUnboundedBelow() || i.min.v.PreRelease != "" || i.max.v.PreRelease != "" {
+		return nil
+	}
+	v := i.min.v
+	forms := []string{fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)}
+	if v.Patch == 0 {
+		forms = append(forms, fmt.Sprintf("%d.%d", v.Major, v.Minor))
+		if v.Minor == 0 {
+			forms = append(forms, fmt.Sprintf("%d", v.Major))
+		}
+	}
+	list := []string{}
+	if i.max.inclusive {
+		return append(list, fmt.Sprintf("%s - %s", v, i.max.v))
+	}
+	for _, f := range forms {
+		list = append(list, f, "^"+f, "~"+f)
+	}
+	return append(list, fmt.Sprintf("%d.%d.x", v.Major, v.Minor), fmt.Sprintf("%d.x", v.Major))
+}
+
+// shortest returns the shortest string describing exactly the interval
+func (i interval) shortest() string {
+	str := i.String()
+	for _, candidate := range i.sugarCandidates() {
+		if len(candidate) >= len(str) {
+			continue
+		}
+		if r, err := ParseRange(candidate); err == nil && r.versionSet().equal(versionSet{i}) {
+			str = candidate
+		}
+	}
+	return str
+}
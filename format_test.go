@@ -0,0 +1,96 @@
+package semver
+
+import "testing"
+
+var canonicalTestBattery = map[string]string{
+	"1.2.3":                           "1.2.3",
+	"=1.2.3":                          "1.2.3",
+	"v1.2.3+build.1":                  "1.2.3",
+	"~1.2":                            ">=1.2.0 <1.3.0",
+	">=1.2.0 <1.3.0":                  ">=1.2.0 <1.3.0",
+	"^0.0.2":                          ">=0.0.2 <0.0.3",
+	"1.x || >=2.5.0 || 5.0.0 - 7.2.3": ">=1.0.0 <2.0.0 || >=2.5.0",
+	"<2.0.0 >=1.0.0 || 0.1.x":         ">=0.1.0 <0.2.0 || >=1.0.0 <2.0.0",
+	">=0.0.0":                         "*",
+	"*.*.*":                           "*",
+	"<*":                              "<0.0.0",
+	"<=1.2.3":                         "<=1.2.3",
+	">1.2":                            ">=1.3.0",
+	"!1.2.3":                          "<1.2.3 || >1.2.3",
+	">=1.2.3-beta.1 <2":               ">=1.2.3-beta.1 <2.0.0",
+}
+
+var shortestTestBattery = map[string]string{
+	">=1.2.3 <2.0.0":           "^1.2.3",
+	">=1.2.0 <2.0.0":           "^1.2",
+	">=1.0.0 <2.0.0":           "1",
+	">=1.2.3 <1.3.0":           "~1.2.3",
+	">=1.2.0 <1.3.0":           "1.2",
+	">=0.2.3 <0.3.0":           "^0.2.3",
+	">=0.0.3 <0.0.4":           "^0.0.3",
+	">=1.2.3 <=2.0.0":          "1.2.3 - 2.0.0",
+	">=1.2.3 <1.2.9":           ">=1.2.3 <1.2.9",
+	">=1.2.3":                  ">=1.2.3",
+	"<1.0.0":                   "<1.0.0",
+	"1.2.3":                    "1.2.3",
+	"*":                        "*",
+	"<*":                       "<0.0.0",
+	"~1.2.3 || >=2.0.0 <3.0.0": "~1.2.3 || 2",
+	">=1.2.3-rc.1 <2.0.0":      ">=1.2.3-rc.1 <2.0.0",
+}
+
+func TestRangeString(t *testing.T) {
+	for str, expected := range canonicalTestBattery {
+		r, err := ParseRange(str)
+		if err != nil {
+			continue
+		}
+		if r.String() != expected {
+			t.Errorf("Expected range %q to be printed as %q but got %q", str, expected, r)
+		}
+	}
+}
+
+func TestCanonical(t *testing.T) {
+	for str, expected := range canonicalTestBattery {
+		if res, err := Canonical(MustParseExpr(str)); err != nil || res != expected {
+			t.Errorf("Expected the canonical form of %q to be %q but got %q (%v)", str, expected, res, err)
+		}
+	}
+}
+
+func TestShortest(t *testing.T) {
+	for str, expected := range shortestTestBattery {
+		if res, err := Shortest(MustParseExpr(str)); err != nil || res != expected {
+			t.Errorf("Expected the shortest form of %q to be %q but got %q (%v)", str, expected, res, err)
+		}
+	}
+}
+
+func TestFormatsRoundTrip(t *testing.T) {
+	for _, battery := range []map[string]map[string]bool{rangeTestBattery, exprTestBattery} {
+		for str, data := range battery {
+			e := MustParseExpr(str)
+			canonical, _ := Canonical(e)
+			shortest, _ := Shortest(e)
+			for _, formatted := range []string{canonical, shortest} {
+				e2 := MustParseExpr(formatted)
+				if res, _ := Canonical(e2); res != canonical {
+					t.Errorf("Expected %q to be equivalent to %q", formatted, str)
+				}
+				for vStr, result := range data {
+					if e2.Matches(MustParseVersion(vStr)) != result {
+						t.Errorf("Expected %q (formatted from %q) of %v to evaluate to %v", formatted, str, vStr, result)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestRangeIsExpression(t *testing.T) {
+	var e Expression = MustParseRange("~1.2")
+	if ok, err := IsSubset(e, MustParseExpr("^1.0.0")); err != nil || !ok {
+		t.Errorf("Expected %q to be a subset of %q", e, "^1.0.0")
+	}
+}
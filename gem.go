@@ -0,0 +1,300 @@
+package semver
+
+import (
+	"fmt"
+	"math/big"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+var (
+	gemVersionRe     = regexp.MustCompile(`^[0-9]+(\.[0-9a-zA-Z]+)*(-[0-9A-Za-z-]+(\.[0-9A-Za-z-]+)*)?$`)
+	gemSegmentRe     = regexp.MustCompile(`[0-9]+|[a-zA-Z]+`)
+	gemRequirementRe = regexp.MustCompile(`^(=|!=|>=|<=|>|<|~>)?\s*`)
+)
+
+// gemSegment is a numeric or alphabetic segment of a gem version. Numbers can have any
+// length, so they are kept as digits without leading zeros
+type gemSegment struct {
+	number string
+	text   string
+}
+
+func (s gemSegment) isText() bool {
+	return s.text != ""
+}
+
+func (s gemSegment) String() string {
+	if s.isText() {
+		return s.text
+	}
+	return s.number
+}
+
+func (s gemSegment) isZero() bool {
+	return s.number == "0"
+}
+
+// compare compares two segments, alphabetic segments being lower than numeric ones
+func (s gemSegment) compare(s2 gemSegment) int {
+	switch {
+	case s.isText() && s2.isText():
+		return strings.Compare(s.text, s2.text)
+	case s.isText():
+		return -1
+	case s2.isText():
+		return 1
+	default:
+		return compareDigits(s.number, s2.number)
+	}
+}
+
+// GemVersion describes a version of a Ruby gem, such as "1.2.3", "1.0.a" or "2.0.0.rc1".
+// Gem versions are made of any number of numeric and alphabetic segments, and they are
+// ordered segment by segment as RubyGems does
+type GemVersion struct {
+	str      string
+	segments []gemSegment
+}
+
+// MustParseGemVersion parses a gem version
+// It panics if it cannot be parsed
+func MustParseGemVersion(str string) *GemVersion {
+	if v, err := ParseGemVersion(str); err != nil {
+		panic(err)
+	} else {
+		return v
+	}
+}
+
+// ParseGemVersion parses a gem version. As in RubyGems, hyphens are replaced by ".pre.",
+// so "1.0.0-rc1" is "1.0.0.pre.rc1"
+func ParseGemVersion(str string) (*GemVersion, error) {
+	trimmed := strings.TrimSpace(str)
+	if !gemVersionRe.MatchString(trimmed) {
+		return nil, fmt.Errorf("malformed gem version string %q", str)
+	}
+	v := &GemVersion{str: strings.Replace(trimmed, "-", ".pre.", -1)}
+	for _, s := range gemSegmentRe.FindAllString(v.str, -1) {
+		if s[0] < '0' || s[0] > '9' {
+			v.segments = append(v.segments, gemSegment{text: s})
+			continue
+		}
+		v.segments = append(v.segments, gemSegment{number: trimDigits(s)})
+	}
+	return v, nil
+}
+
+// newGemVersion returns the gem version made of the segments
+func newGemVersion(segments []gemSegment) *GemVersion {
+	list := []string{}
+	for _, s := range segments {
+		list = append(list, s.String())
+	}
+	return &GemVersion{str: strings.Join(list, "."), segments: segments}
+}
+
+// gemVersionOf returns the gem version equivalent to the semver v, ignoring its build metadata.
+// It returns false if the pre-release of v cannot be written as gem segments, as "a..b"
+func gemVersionOf(v *Version) (*GemVersion, bool) {
+	str := fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+	if v.PreRelease != "" {
+		str += "-" + v.PreRelease
+	}
+	gv, err := ParseGemVersion(str)
+	if err != nil {
+		return nil, false
+	}
+	return gv, true
+}
+
+func (v *GemVersion) String() string {
+	return v.str
+}
+
+// IsPreRelease returns true if the version contains any alphabetic segment, as "1.0.a" or "2.0.0.rc1"
+func (v *GemVersion) IsPreRelease() bool {
+	for _, s := range v.segments {
+		if s.isText() {
+			return true
+		}
+	}
+	return false
+}
+
+// release returns the segments of the version preceding its first alphabetic segment
+func (v *GemVersion) release() []gemSegment {
+	for i, s := range v.segments {
+		if s.isText() {
+			return append([]gemSegment{}, v.segments[:i]...)
+		}
+	}
+	return append([]gemSegment{}, v.segments...)
+}
+
+// Release returns the version without its pre-release segments: the release of "1.2.0.rc1" is "1.2.0"
+func (v *GemVersion) Release() *GemVersion {
+	if !v.IsPreRelease() {
+		return v
+	}
+	return newGemVersion(v.release())
+}
+
+// Bump returns the version used as upper limit by the pessimistic operator "~>": pre-release
+// segments and the last segment are dropped and the new last segment is incremented.
+// For example, the bump of "1.2.3" is "1.3" and the bump of "1.2" is "2"
+func (v *GemVersion) Bump() *GemVersion {
+	segments := v.release()
+	if len(segments) > 1 {
+		segments = segments[:len(segments)-1]
+	}
+	n, _ := new(big.Int).SetString(segments[len(segments)-1].number, 10)
+	segments[len(segments)-1].number = n.Add(n, big.NewInt(1)).String()
+	return newGemVersion(segments)
+}
+
+// canonicalSegments returns the segments without the trailing zeros of both the release
+// and the pre-release parts, which do not affect the ordering: "1.0.0.a.0" is "1.a"
+func (v *GemVersion) canonicalSegments() []gemSegment {
+	trim := func(list []gemSegment) []gemSegment {
+		for len(list) > 0 && list[len(list)-1].isZero() {
+			list = list[:len(list)-1]
+		}
+		return list
+	}
+	release := v.release()
+	return append(trim(release), trim(append([]gemSegment{}, v.segments[len(release):]...))...)
+}
+
+// Compare returns -1, 0 or 1 if v is respectively lower, equal or higher than v2. Segments are
+// compared one by one, missing segments being zero and alphabetic segments lower than numeric ones,
+// so "1.0.a" < "1.0" = "1.0.0" < "1.0.1"
+func (v *GemVersion) Compare(v2 *GemVersion) int {
+	s1, s2 := v.canonicalSegments(), v2.canonicalSegments()
+	for i := 0; i < len(s1) || i < len(s2); i++ {
+		e1, e2 := gemSegment{number: "0"}, gemSegment{number: "0"}
+		if i < len(s1) {
+			e1 = s1[i]
+		}
+		if i < len(s2) {
+			e2 = s2[i]
+		}
+		if res := e1.compare(e2); res != 0 {
+			return res
+		}
+	}
+	return 0
+}
+
+// GemVersions defines a list of gem versions sortable in ascending order. It implements sort.Interface
+type GemVersions []*GemVersion
+
+func (vs GemVersions) Len() int           { return len(vs) }
+func (vs GemVersions) Swap(i, j int)      { vs[i], vs[j] = vs[j], vs[i] }
+func (vs GemVersions) Less(i, j int) bool { return vs[i].Compare(vs[j]) < 0 }
+
+// SortGemVersions sorts the gem versions in ascending order. Equal versions, such as "1.0"
+// and "1.0.0", keep their relative order
+func SortGemVersions(versions []*GemVersion) {
+	sort.Stable(GemVersions(versions))
+}
+
+// gemConstraint is a single constraint of a gem requirement, such as "~> 1.2"
+type gemConstraint struct {
+	operator string
+	version  *GemVersion
+}
+
+func (c gemConstraint) satisfiedBy(v *GemVersion) bool {
+	switch res := v.Compare(c.version); c.operator {
+	case "!=":
+		return res != 0
+	case ">":
+		return res > 0
+	case "<":
+		return res < 0
+	case ">=":
+		return res >= 0
+	case "<=":
+		return res <= 0
+	case "~>":
+		return res >= 0 && v.Release().Compare(c.version.Bump()) < 0
+	default:
+		return res == 0
+	}
+}
+
+// GemRequirement describes a requirement of RubyGems or Bundler, such as "~> 1.2, != 1.2.5".
+// Its "~>" is the pessimistic operator, unlike the npm alias of "~" accepted by ParseRange.
+// It implements the Expression interface by converting the versions into gem versions, but the set
+// operations, which follow the SemVer ordering, return an error for it
+type GemRequirement struct {
+	str         string
+	constraints []gemConstraint
+}
+
+// MustParseGemRequirement parses a gem requirement
+// It panics if it cannot be parsed
+func MustParseGemRequirement(str string) *GemRequirement {
+	if r, err := ParseGemRequirement(str); err != nil {
+		panic(err)
+	} else {
+		return r
+	}
+}
+
+// ParseGemRequirement parses a list of comma separated gem constraints, made of an optional operator
+// ("=", "!=", ">", "<", ">=", "<=" or "~>") and a gem version. A bare version means "=", and an empty
+// requirement is ">= 0". Unlike in ParseRange, where "~>" is an alias of "~", the pessimistic
+// operator lets the last specified segment go up: "~> 1.2" is ">= 1.2, < 2" and "~> 1.2.3" is
+// ">= 1.2.3, < 1.3". Syntax errors are reported as a *ParseError
+func ParseGemRequirement(str string) (*GemRequirement, error) {
+	r := &GemRequirement{str: str}
+	if strings.TrimSpace(str) == "" {
+		r.constraints = []gemConstraint{{operator: ">=", version: MustParseGemVersion("0")}}
+		return r, nil
+	}
+	offset := 0
+	for _, item := range strings.Split(str, ",") {
+		trimmed := strings.TrimSpace(item)
+		start := offset + len(item) - len(strings.TrimLeftFunc(item, unicode.IsSpace))
+		offset += len(item) + 1
+		m := gemRequirementRe.FindStringSubmatch(trimmed)
+		versionStart := start + len(m[0])
+		v, err := ParseGemVersion(str[versionStart : offset-1])
+		if err != nil {
+			token := ""
+			if versionStart < len(str) {
+				token = str[versionStart : versionStart+1]
+			}
+			return nil, &ParseError{Input: str, Offset: versionStart, Token: token, Expected: "gem version"}
+		}
+		r.constraints = append(r.constraints, gemConstraint{operator: m[1], version: v})
+	}
+	return r, nil
+}
+
+func (r *GemRequirement) String() string {
+	return r.str
+}
+
+// SatisfiedBy checks if the gem version v satisfies all the constraints of the requirement.
+// Pre-release versions are not excluded on their own: "> 1" is satisfied by "2.0.a"
+func (r *GemRequirement) SatisfiedBy(v *GemVersion) bool {
+	for _, c := range r.constraints {
+		if !c.satisfiedBy(v) {
+			return false
+		}
+	}
+	return true
+}
+
+// Matches checks if the requirement is satisfied by the gem version equivalent to v, in which
+// the pre-release is made of segments following ".pre.": "1.0.0-rc.1" is "1.0.0.pre.rc.1".
+// Versions without gem equivalent never match
+func (r *GemRequirement) Matches(v *Version) bool {
+	gv, ok := gemVersionOf(v)
+	return ok && r.SatisfiedBy(gv)
+}
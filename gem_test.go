@@ -0,0 +1,163 @@
+package semver
+
+import (
+	"sort"
+	"testing"
+)
+
+func TestGemVersionCompare(t *testing.T) {
+	// Cases from the RubyGems test suite
+	for _, c := range []struct {
+		v1, v2   string
+		expected int
+	}{
+		{"1.0", "1.0.0", 0},
+		{"1", "1.0.0", 0},
+		{"1.0", "1.0.a", 1},
+		{"1.8.2", "0.0.0", 1},
+		{"1.8.2", "1.8.2.a", 1},
+		{"1.8.2.b", "1.8.2.a", 1},
+		{"1.8.2.a", "1.8.2", -1},
+		{"1.8.2.a10", "1.8.2.a9", 1},
+		{"0.beta.1", "0.0.beta.1", 0},
+		{"0.0.beta", "0.0.beta.1", -1},
+		{"0.0.beta", "0.beta.1", -1},
+		{"5.a", "5.0.0.rc2", -1},
+		{"5.x", "5.0.0.rc2", 1},
+		{"1.0.0-rc1", "1.0.0.pre.rc1", 0},
+		{"1.10", "1.9", 1},
+		{"2.0.0.pre", "1.99999999999999999999", 1},
+	} {
+		v1, v2 := MustParseGemVersion(c.v1), MustParseGemVersion(c.v2)
+		if res := v1.Compare(v2); res != c.expected {
+			t.Errorf("Expected the comparison of gem versions %q and %q to be %d but got %d", c.v1, c.v2, c.expected, res)
+		}
+		if res := v2.Compare(v1); res != -c.expected {
+			t.Errorf("Expected the comparison of gem versions %q and %q to be %d but got %d", c.v2, c.v1, -c.expected, res)
+		}
+	}
+}
+
+func TestSortGemVersions(t *testing.T) {
+	sorted := []string{"0.9", "1.0.a", "1.0.a.1", "1.0.b", "1.0.rc1", "1", "1.0.0", "1.0.1", "1.2", "1.10.0", "2.0.0.pre.alpha"}
+	versions := []*GemVersion{}
+	for i := len(sorted) - 1; i >= 0; i-- {
+		versions = append(versions, MustParseGemVersion(sorted[i]))
+	}
+	// "1" and "1.0.0" are equal, so they are swapped by the stable sort of the reversed list
+	sorted[5], sorted[6] = sorted[6], sorted[5]
+	SortGemVersions(versions)
+	for i, v := range versions {
+		if v.String() != sorted[i] {
+			t.Errorf("Expected gem version %d to be %q but got %q", i, sorted[i], v)
+		}
+	}
+	if !sort.IsSorted(GemVersions(versions)) {
+		t.Errorf("Expected gem versions to be sorted")
+	}
+}
+
+func TestParseGemVersion(t *testing.T) {
+	for str, expected := range map[string]string{
+		"1.2.3":        "1.2.3",
+		" 1.0 ":        "1.0",
+		"1.0.0-rc1":    "1.0.0.pre.rc1",
+		"1.0.0.beta.2": "1.0.0.beta.2",
+		"2.0b1":        "2.0b1",
+	} {
+		if v, err := ParseGemVersion(str); err != nil || v.String() != expected {
+			t.Errorf("Expected gem version %q to be parsed as %q but got %v (%v)", str, expected, v, err)
+		}
+	}
+	for _, str := range []string{"", "junk", "1.0\n2.0", "1..2", "1.2 3.4", "v1.2", "1.2.", "1.0+build"} {
+		if _, err := ParseGemVersion(str); err == nil {
+			t.Errorf("Expected gem version %q to be invalid", str)
+		}
+	}
+}
+
+func TestGemVersionReleaseAndBump(t *testing.T) {
+	for str, expected := range map[string][2]string{
+		"5.2.4":        {"5.2.4", "5.3"},
+		"5.2.4.a":      {"5.2.4", "5.3"},
+		"5.2.4.a10":    {"5.2.4", "5.3"},
+		"5.0.0":        {"5.0.0", "5.1"},
+		"5":            {"5", "6"},
+		"1.9.3.alpha5": {"1.9.3", "1.10"},
+		"1.1.rc10":     {"1.1", "2"},
+	} {
+		v := MustParseGemVersion(str)
+		if res := v.Release().String(); res != expected[0] {
+			t.Errorf("Expected the release of gem version %q to be %q but got %q", str, expected[0], res)
+		}
+		if res := v.Bump().String(); res != expected[1] {
+			t.Errorf("Expected the bump of gem version %q to be %q but got %q", str, expected[1], res)
+		}
+		if v.IsPreRelease() != (v.Release() != v) {
+			t.Errorf("Expected gem version %q to be a pre-release: %v", str, v.Release() != v)
+		}
+	}
+}
+
+func TestGemRequirementSatisfiedBy(t *testing.T) {
+	for req, data := range map[string]map[string]bool{
+		"~> 1.2":            {"1.2": true, "1.9.9": true, "2.0": false, "1.1": false, "2.0.a": false, "1.5.rc1": true},
+		"~> 1.2.3":          {"1.2.3": true, "1.2.10": true, "1.3": false, "1.3.0.a": false, "1.2.3.a": false},
+		"~> 1":              {"1.0": true, "1.9": true, "2": false},
+		"~> 1.4.4":          {"1.4.4": true, "1.4.5": true, "1.5.0": false, "1.4.4.1": true},
+		"~> 1.0.0.a":        {"1.0.0.a": true, "1.0.0.b": true, "1.0.1": true, "1.1.0": false},
+		">= 1.0, < 2":       {"1.0": true, "1.99": true, "2.0.0": false, "2.0.a": true, "0.9": false},
+		"~> 1.2, != 1.2.5":  {"1.2.4": true, "1.2.5": false, "1.2.5.0": false, "1.2.6": true},
+		"1.2":               {"1.2": true, "1.2.0": true, "1.2.0.a": false},
+		"= 1.2":             {"1.2.0": true, "1.2.1": false},
+		"> 1":               {"2.0.a": true, "1.0": false},
+		"<= 1.0":            {"1.0.0": true, "1.0.a": true, "1.0.1": false},
+		"":                  {"0": true, "0.0.a": false, "1.0.0": true},
+		" ~>1.2 ,  >=1.2.5": {"1.2.4": false, "1.2.5": true, "1.9": true},
+	} {
+		r := MustParseGemRequirement(req)
+		for v, expected := range data {
+			if r.SatisfiedBy(MustParseGemVersion(v)) != expected {
+				t.Errorf("Expected gem requirement %q to be satisfied by %q: %v", req, v, expected)
+			}
+		}
+		if r.String() != req {
+			t.Errorf("Expected the string of gem requirement %q to be the original input but got %q", req, r)
+		}
+	}
+}
+
+func TestGemRequirementMatches(t *testing.T) {
+	var e Expression = MustParseGemRequirement("~> 1.2, != 1.5.0")
+	for v, expected := range map[string]bool{
+		"1.2.0":         true,
+		"1.9.9-rc.1":    true,
+		"1.5.0":         false,
+		"1.5.0+build.1": false,
+		"2.0.0-alpha":   false,
+		"1.1.9":         false,
+		"1.5.1-a..b":    false,
+		"1.5.1-a.b!":    false,
+	} {
+		if e.Matches(MustParseVersion(v)) != expected {
+			t.Errorf("Expected gem requirement %q to match %q: %v", e, v, expected)
+		}
+	}
+}
+
+func TestGemRequirementErrors(t *testing.T) {
+	for req, offset := range map[string]int{
+		"~>":             2,
+		"1.2,":           4,
+		">= 1.0, < junk": 10,
+		"=> 1.0":         1,
+		"~> 1.2 || 2.0":  3,
+		"1.2, ,1.3":      5,
+		"^1.2":           0,
+	} {
+		_, err := ParseGemRequirement(req)
+		if pErr, ok := err.(*ParseError); !ok || pErr.Offset != offset {
+			t.Errorf("Expected gem requirement %q to fail at offset %d but got %v", req, offset, err)
+		}
+	}
+}
@@ -0,0 +1,232 @@
+package semver
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var goNumberStr = `(0|[1-9][0-9]*)`
+var goPreReleaseIDStr = `(?:0|[1-9][0-9]*|[0-9]*[a-zA-Z-][0-9a-zA-Z-]*)`
+var goModuleVersionRe = regexp.MustCompile(
+	fmt.Sprintf(
+		`^v%s`+
+			// Followed by an optional .Minor.Patch-preRelease+build
+			`(?:\.%s`+
+			`(?:\.%s`+
+			// -preRelease, whose numeric identifiers have no leading zeros
+			`(?:-(%s(?:\.%s)*))?`+
+			// build
+			`(?:\+([0-9a-zA-Z-]+(?:\.[0-9a-zA-Z-]+)*))?`+
+			`)?`+
+			`)?$`,
+		goNumberStr, goNumberStr, goNumberStr, goPreReleaseIDStr, goPreReleaseIDStr,
+	),
+)
+
+// goPseudoVersionRe matches the pre-release of the three forms of pseudo-versions: "yyyymmddhhmmss-rev",
+// "pre.0.yyyymmddhhmmss-rev" and "0.yyyymmddhhmmss-rev"
+var goPseudoVersionRe = regexp.MustCompile(`^(?:(?:[^+]*\.)?0\.)?[0-9]{14}-[A-Za-z0-9]+$`)
+
+const goPseudoTimeLayout = "20060102150405"
+
+// GoModuleVersion describes a version of a Go module, such as "v1.2.3", "v2.3.4+incompatible" or the
+// pseudo-version "v0.0.0-20191109021931-daa7c04131f5". Go module versions are SemVer versions with
+// a mandatory "v" prefix, in which "v1" and "v1.2" are shorthands for "v1.0.0" and "v1.2.0"
+type GoModuleVersion struct {
+	str                 string
+	major, minor, patch int64
+	preRelease          string
+	build               string
+}
+
+// MustParseGoModuleVersion parses a Go module version
+// It panics if it cannot be parsed
+func MustParseGoModuleVersion(str string) *GoModuleVersion {
+	if v, err := ParseGoModuleVersion(str); err != nil {
+		panic(err)
+	} else {
+		return v
+	}
+}
+
+// ParseGoModuleVersion parses a Go module version. As in the go command, the "+incompatible" build
+// is only allowed for major versions 2 and higher, used by modules without a go.mod file
+func ParseGoModuleVersion(str string) (*GoModuleVersion, error) {
+	m := goModuleVersionRe.FindStringSubmatch(str)
+	if m == nil {
+		return nil, fmt.Errorf("malformed Go module version string %q", str)
+	}
+	v := &GoModuleVersion{str: str, preRelease: m[4], build: m[5]}
+	for i, n := range []*int64{&v.major, &v.minor, &v.patch} {
+		if m[i+1] == "" {
+			continue
+		}
+		var err error
+		if *n, err = strconv.ParseInt(m[i+1], 10, 64); err != nil {
+			return nil, fmt.Errorf("malformed Go module version string %q", str)
+		}
+	}
+	if v.IsIncompatible() && v.major < 2 {
+		return nil, fmt.Errorf("malformed Go module version string %q: +incompatible requires a major version 2 or higher", str)
+	}
+	return v, nil
+}
+
+func (v *GoModuleVersion) String() string {
+	return v.str
+}
+
+// Version returns the SemVer version equivalent to v, without the "v" prefix, so that it can be
+// compared with other versions or matched by ranges
+func (v *GoModuleVersion) Version() *Version {
+	return NewVersion(v.major, v.minor, v.patch, v.preRelease, v.build)
+}
+
+// Canonical returns the form of the version used in go.mod files: shorthands are completed and
+// the build metadata is removed, except for "+incompatible". "v1.2" is "v1.2.0" and
+// "v1.2.3+meta" is "v1.2.3"
+func (v *GoModuleVersion) Canonical() string {
+	str := fmt.Sprintf("v%d.%d.%d", v.major, v.minor, v.patch)
+	if v.preRelease != "" {
+		str += "-" + v.preRelease
+	}
+	if v.IsIncompatible() {
+		str += "+incompatible"
+	}
+	return str
+}
+
+// Compare returns -1, 0 or 1 if v is respectively lower, equal or higher than v2, following the
+// SemVer precedence rules. Build metadata, including "+incompatible", is ignored. Pseudo-versions are
+// pre-releases, so they are lower than the release that follows their base version
+func (v *GoModuleVersion) Compare(v2 *GoModuleVersion) int {
+	return v.Version().compare(v2.Version())
+}
+
+// IsIncompatible returns true if the version has the "+incompatible" build, used for major
+// versions 2 and higher of modules that do not follow the major version suffix convention
+func (v *GoModuleVersion) IsIncompatible() bool {
+	return v.build == "incompatible"
+}
+
+// IsPseudo returns true if the version is a pseudo-version, which identifies a commit without
+// a tag, such as "v0.0.0-20191109021931-daa7c04131f5", "v1.2.4-0.20191109021931-daa7c04131f5"
+// or "v1.2.3-pre.0.20191109021931-daa7c04131f5"
+func (v *GoModuleVersion) IsPseudo() bool {
+	if !goPseudoVersionRe.MatchString(v.preRelease) {
+		return false
+	}
+	// Pseudo-versions without a base version are vX.0.0-yyyymmddhhmmss-rev
+	return strings.Contains(v.preRelease, ".") || (v.minor == 0 && v.patch == 0)
+}
+
+// splitPseudo returns the timestamp and the revision of a pseudo-version
+func (v *GoModuleVersion) splitPseudo() (string, string, error) {
+	if !v.IsPseudo() {
+		return "", "", fmt.Errorf("%q is not a pseudo-version", v.str)
+	}
+	i := strings.LastIndex(v.preRelease, "-")
+	timestamp := v.preRelease[:i]
+	return timestamp[strings.LastIndex(timestamp, ".")+1:], v.preRelease[i+1:], nil
+}
+
+// PseudoTime returns the UTC time of the commit of a pseudo-version
+func (v *GoModuleVersion) PseudoTime() (time.Time, error) {
+	timestamp, _, err := v.splitPseudo()
+	if err != nil {
+		return time.Time{}, err
+	}
+	t, err := time.Parse(goPseudoTimeLayout, timestamp)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("malformed time in pseudo-version %q", v.str)
+	}
+	return t, nil
+}
+
+// PseudoRev returns the revision identifier of a pseudo-version, usually an abbreviated commit hash
+func (v *GoModuleVersion) PseudoRev() (string, error) {
+	_, rev, err := v.splitPseudo()
+	return rev, err
+}
+
+// Major returns the major version prefix, such as "v2"
+func (v *GoModuleVersion) Major() string {
+	return fmt.Sprintf("v%d", v.major)
+}
+
+// PathMajor returns the major version suffix that the module path must have for the version,
+// such as "/v2". It is empty for major versions 0 and 1 and for "+incompatible" versions
+func (v *GoModuleVersion) PathMajor() string {
+	if v.major < 2 || v.IsIncompatible() {
+		return ""
+	}
+	return "/" + v.Major()
+}
+
+// MatchesPathMajor checks if the version is allowed for a module whose path has the major version
+// suffix pathMajor, as returned by SplitGoModulePath: "/v2" and ".v2" (gopkg.in) require v2 versions,
+// and an empty suffix requires v0 or v1 versions, or "+incompatible" ones. As in the go command,
+// gopkg.in ".v1" paths also accept v0.0.0 pseudo-versions, which older releases generated for them
+func (v *GoModuleVersion) MatchesPathMajor(pathMajor string) bool {
+	switch {
+	case pathMajor == "":
+		return v.major < 2 || v.IsIncompatible()
+	case v.IsIncompatible():
+		return false
+	case strings.HasPrefix(pathMajor, "/"):
+		return pathMajor == "/"+v.Major()
+	}
+	major := strings.TrimSuffix(pathMajor[1:], "-unstable")
+	if major == "v1" && v.IsPseudo() && v.major == 0 && v.minor == 0 && v.patch == 0 {
+		return true
+	}
+	return major == v.Major()
+}
+
+// goMajorSuffixRe matches the last path element of modules with major version 2 or higher
+var goMajorSuffixRe = regexp.MustCompile(`^v(?:[2-9]|[1-9][0-9]+)$`)
+
+// gopkgInPathRe matches gopkg.in paths, which always end with a major version such as ".v1" or
+// ".v2-unstable"
+var gopkgInPathRe = regexp.MustCompile(`^(gopkg\.in/.+)(\.v(?:0|[1-9][0-9]*)(?:-unstable)?)$`)
+
+// SplitGoModulePath splits a module path into a prefix and a major version suffix, such as
+// "example.com/mod" and "/v2" for "example.com/mod/v2", or "gopkg.in/yaml" and ".v2" for
+// "gopkg.in/yaml.v2". The suffix is empty if the path has none. ok is false if the suffix is
+// not valid, as in "example.com/mod/v1" or "example.com/mod/v2.1"
+func SplitGoModulePath(path string) (prefix, pathMajor string, ok bool) {
+	if strings.HasPrefix(path, "gopkg.in/") {
+		m := gopkgInPathRe.FindStringSubmatch(path)
+		if m == nil {
+			return path, "", false
+		}
+		return m[1], m[2], true
+	}
+	i := strings.LastIndex(path, "/")
+	elem := path[i+1:]
+	// Only elements made of a "v" followed by digits and dots look like a major version
+	if i <= 0 || len(elem) < 2 || elem[0] != 'v' || strings.Trim(elem[1:], "0123456789.") != "" {
+		return path, "", true
+	}
+	if !goMajorSuffixRe.MatchString(elem) {
+		return path, "", false
+	}
+	return path[:i], path[i:], true
+}
+
+// GoModuleVersions defines a list of Go module versions sortable in ascending order. It implements sort.Interface
+type GoModuleVersions []*GoModuleVersion
+
+func (vs GoModuleVersions) Len() int           { return len(vs) }
+func (vs GoModuleVersions) Swap(i, j int)      { vs[i], vs[j] = vs[j], vs[i] }
+func (vs GoModuleVersions) Less(i, j int) bool { return vs[i].Compare(vs[j]) < 0 }
+
+// SortGoModuleVersions sorts the Go module versions in ascending order. Equal versions, such as
+// "v2.0.0" and "v2.0.0+incompatible", keep their relative order
+func SortGoModuleVersions(versions []*GoModuleVersion) {
+	sort.Stable(GoModuleVersions(versions))
+}
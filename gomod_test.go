@@ -0,0 +1,191 @@
+package semver
+
+import (
+	"testing"
+	"time"
+)
+
+var goModuleOrderedVersions = []string{
+	"v0.0.0-20191109021931-daa7c04131f5", "v0.0.0-20200101000000-abcdefabcdef", "v0.0.0", "v0.1.0",
+	"v1.2.3-pre", "v1.2.3-pre.0.20191109021931-daa7c04131f5", "v1.2.3", "v1.2.4-0.20191109021931-daa7c04131f5",
+	"v1.2.4", "v1.10.0", "v2.0.0-rc.1", "v2.0.0+incompatible", "v2.3.4+incompatible",
+}
+
+func TestGoModuleVersionOrdering(t *testing.T) {
+	for i, str := range goModuleOrderedVersions {
+		v := MustParseGoModuleVersion(str)
+		for j, str2 := range goModuleOrderedVersions {
+			expected := compareInt(i, j)
+			if res := v.Compare(MustParseGoModuleVersion(str2)); res != expected {
+				t.Errorf("Expected the comparison of Go module versions %q and %q to be %d but got %d", str, str2, expected, res)
+			}
+		}
+	}
+	if res := MustParseGoModuleVersion("v2.0.0+incompatible").Compare(MustParseGoModuleVersion("v2")); res != 0 {
+		t.Errorf("Expected +incompatible to be ignored when comparing Go module versions but got %d", res)
+	}
+}
+
+func TestGoModuleVersionCanonical(t *testing.T) {
+	for str, expected := range map[string]string{
+		"v1":                                 "v1.0.0",
+		"v1.2":                               "v1.2.0",
+		"v1.2.3":                             "v1.2.3",
+		"v1.2.3-rc.1+meta":                   "v1.2.3-rc.1",
+		"v2.3.4+incompatible":                "v2.3.4+incompatible",
+		"v0.0.0-20191109021931-daa7c04131f5": "v0.0.0-20191109021931-daa7c04131f5",
+		"v10.20.30-alpha.0.x-y":              "v10.20.30-alpha.0.x-y",
+		"v9223372036854775807.0.0":           "v9223372036854775807.0.0",
+	} {
+		v, err := ParseGoModuleVersion(str)
+		if err != nil {
+			t.Errorf("Expected %q to be a valid Go module version but got %v", str, err)
+			continue
+		}
+		if res := v.Canonical(); res != expected {
+			t.Errorf("Expected the canonical form of Go module version %q to be %q but got %q", str, expected, res)
+		}
+		if v.String() != str {
+			t.Errorf("Expected the string of Go module version %q to be the original input but got %q", str, v)
+		}
+	}
+	for _, str := range []string{
+		"", "1.2.3", "v", "v01.2.3", "v1.02.3", "v1.2.3-01", "v1.2-pre", "v1.2.3-", "v1.2.3+", " v1.2.3",
+		"v1.2.3.4", "v1.0.0+incompatible", "v0.1.0+incompatible", "v1+incompatible", "v9223372036854775808.0.0",
+	} {
+		if _, err := ParseGoModuleVersion(str); err == nil {
+			t.Errorf("Expected Go module version %q to be invalid", str)
+		}
+	}
+	if v := MustParseGoModuleVersion("v1.2.3-rc.1+meta").Version(); !v.Equal(MustParseVersion("1.2.3-rc.1")) || v.Build != "meta" {
+		t.Errorf("Expected the SemVer version of a Go module version to keep its components but got %v", v)
+	}
+}
+
+func TestGoModulePseudoVersions(t *testing.T) {
+	for str, rev := range map[string]string{
+		"v0.0.0-20191109021931-daa7c04131f5":              "daa7c04131f5",
+		"v1.2.4-0.20191109021931-daa7c04131f5":            "daa7c04131f5",
+		"v1.2.3-pre.0.20191109021931-daa7c04131f5":        "daa7c04131f5",
+		"v2.0.0-20191109021931-daa7c04131f5+incompatible": "daa7c04131f5",
+		"v3.0.1-0.20191109021931-abc":                     "abc",
+	} {
+		v := MustParseGoModuleVersion(str)
+		if !v.IsPseudo() {
+			t.Errorf("Expected %q to be a pseudo-version", str)
+			continue
+		}
+		if res, err := v.PseudoRev(); err != nil || res != rev {
+			t.Errorf("Expected the revision of pseudo-version %q to be %q but got %q (%v)", str, rev, res, err)
+		}
+		expected := time.Date(2019, time.November, 9, 2, 19, 31, 0, time.UTC)
+		if res, err := v.PseudoTime(); err != nil || !res.Equal(expected) {
+			t.Errorf("Expected the time of pseudo-version %q to be %v but got %v (%v)", str, expected, res, err)
+		}
+	}
+	for _, str := range []string{
+		"v1.2.3", "v1.2.3-pre", "v1.2.4-20191109021931-daa7c04131f5", "v0.0.0-2019110902193-daa7c04131f5",
+		"v1.2.4-1.20191109021931-daa7c04131f5", "v0.0.0-20191109021931",
+	} {
+		v := MustParseGoModuleVersion(str)
+		if v.IsPseudo() {
+			t.Errorf("Expected %q not to be a pseudo-version", str)
+		}
+		if _, err := v.PseudoRev(); err == nil {
+			t.Errorf("Expected the revision of %q to fail", str)
+		}
+		if _, err := v.PseudoTime(); err == nil {
+			t.Errorf("Expected the time of %q to fail", str)
+		}
+	}
+	if _, err := MustParseGoModuleVersion("v0.0.0-20191399021931-daa7c04131f5").PseudoTime(); err == nil {
+		t.Errorf("Expected the time of a pseudo-version with an invalid date to fail")
+	}
+}
+
+func TestGoModulePathMajor(t *testing.T) {
+	for str, expected := range map[string][2]string{
+		"v0.1.0":              {"v0", ""},
+		"v1.2.3":              {"v1", ""},
+		"v2.0.0":              {"v2", "/v2"},
+		"v2.3.4+incompatible": {"v2", ""},
+		"v10.0.0-rc.1":        {"v10", "/v10"},
+	} {
+		v := MustParseGoModuleVersion(str)
+		if res := [2]string{v.Major(), v.PathMajor()}; res != expected {
+			t.Errorf("Expected the major version of %q to be %v but got %v", str, expected, res)
+		}
+		if !v.MatchesPathMajor(v.PathMajor()) {
+			t.Errorf("Expected %q to match its own path major %q", str, v.PathMajor())
+		}
+	}
+	for _, test := range []struct {
+		version, pathMajor string
+		expected           bool
+	}{
+		{"v1.2.3", "/v2", false},
+		{"v2.0.0", "", false},
+		{"v2.0.0+incompatible", "/v2", false},
+		{"v3.1.0", "/v2", false},
+		{"v2.1.0", ".v2", true},
+		{"v2.1.0", ".v2-unstable", true},
+		{"v1.0.0", ".v1", true},
+		{"v0.3.0", ".v0", true},
+		{"v0.3.0", ".v1", false},
+		{"v0.0.0-20161208181325-20d25e280405", ".v1", true},
+		{"v0.0.0-20161208181325-20d25e280405", ".v1-unstable", true},
+		{"v0.0.0-20161208181325-20d25e280405", ".v2", false},
+		{"v0.0.0-20161208181325-20d25e280405", "/v2", false},
+	} {
+		if res := MustParseGoModuleVersion(test.version).MatchesPathMajor(test.pathMajor); res != test.expected {
+			t.Errorf("Expected %q to match the path major %q: %v", test.version, test.pathMajor, test.expected)
+		}
+	}
+}
+
+func TestSplitGoModulePath(t *testing.T) {
+	for _, test := range []struct {
+		path, prefix, pathMajor string
+		ok                      bool
+	}{
+		{"example.com/mod", "example.com/mod", "", true},
+		{"example.com/mod/v2", "example.com/mod", "/v2", true},
+		{"example.com/mod/v10", "example.com/mod", "/v10", true},
+		{"example.com/mod/v1", "example.com/mod/v1", "", false},
+		{"example.com/mod/v0", "example.com/mod/v0", "", false},
+		{"example.com/mod/v02", "example.com/mod/v02", "", false},
+		{"example.com/mod/v2.1", "example.com/mod/v2.1", "", false},
+		{"example.com/mod/v", "example.com/mod/v", "", true},
+		{"example.com/modv2", "example.com/modv2", "", true},
+		{"example.com/mod/v2.", "example.com/mod/v2.", "", false},
+		{"example.com/mod/vendor", "example.com/mod/vendor", "", true},
+		{"v2", "v2", "", true},
+		{"gopkg.in/yaml.v2", "gopkg.in/yaml", ".v2", true},
+		{"gopkg.in/check.v1", "gopkg.in/check", ".v1", true},
+		{"gopkg.in/foo.v0", "gopkg.in/foo", ".v0", true},
+		{"gopkg.in/foo.v3-unstable", "gopkg.in/foo", ".v3-unstable", true},
+		{"gopkg.in/yaml", "gopkg.in/yaml", "", false},
+		{"gopkg.in/foo.v01", "gopkg.in/foo.v01", "", false},
+		{"gopkg.in/foo.v1.v2", "gopkg.in/foo.v1", ".v2", true},
+		{"gopkg.in/foo.v2-unstable.x", "gopkg.in/foo.v2-unstable.x", "", false},
+	} {
+		prefix, pathMajor, ok := SplitGoModulePath(test.path)
+		if prefix != test.prefix || pathMajor != test.pathMajor || ok != test.ok {
+			t.Errorf("Expected %q to be split into %q, %q, %v but got %q, %q, %v",
+				test.path, test.prefix, test.pathMajor, test.ok, prefix, pathMajor, ok)
+		}
+	}
+}
+
+func TestSortGoModuleVersions(t *testing.T) {
+	versions := []*GoModuleVersion{}
+	for i := len(goModuleOrderedVersions) - 1; i >= 0; i-- {
+		versions = append(versions, MustParseGoModuleVersion(goModuleOrderedVersions[i]))
+	}
+	SortGoModuleVersions(versions)
+	for i, v := range versions {
+		if v.String() != goModuleOrderedVersions[i] {
+			t.Errorf("Expected Go module version %d to be %q but got %q", i, goModuleOrderedVersions[i], v)
+		}
+	}
+}
@@ -23,3 +23,13 @@ var SupportRevisionsInPreRelease = WithPreReleaseHandler(func(pr1, pr2 string) (
 	}
 	return comparePreReleases(pr1, pr2)
 })
+
+// HeuristicPreReleases hack compares pre-releases by recognizing common stability
+// indicators (pre-alpha < alpha < beta < rc < final) and their revision, instead of following
+// the SemVer 2.0 precedence rules. Indicators are compared as strings if unknown
+var HeuristicPreReleases = WithPreReleaseHandler(comparePreReleases)
+
+// MavenPreReleases hack compares pre-releases as qualifiers of Maven versions, ordered as
+// alpha < beta < milestone < rc < snapshot < "" (release) < sp, so "1.0.0-SNAPSHOT" < "1.0.0" < "1.0.0-sp1".
+// Ranges only contain pre-releases such as "sp1" if they are allowed, as with IncludePrerelease
+var MavenPreReleases = WithPreReleaseHandler(compareMavenQualifiers)
@@ -0,0 +1,159 @@
+package semver
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var preReleaseIDRe = regexp.MustCompile(`^[0-9A-Za-z-]+$`)
+
+// IncKind defines the kind of increment to apply to a version
+type IncKind string
+
+// Supported increments, following the semantics of npm's semver
+const (
+	// IncMajor increments the major version: 1.2.3 -> 2.0.0. The pre-release of
+	// a major version is released instead: 2.0.0-rc.1 -> 2.0.0
+	IncMajor IncKind = "major"
+	// IncMinor increments the minor version: 1.2.3 -> 1.3.0. The pre-release of
+	// a minor version is released instead: 1.3.0-rc.1 -> 1.3.0
+	IncMinor IncKind = "minor"
+	// IncPatch increments the patch version: 1.2.3 -> 1.2.4. Pre-releases are
+	// released instead: 1.2.4-rc.1 -> 1.2.4
+	IncPatch IncKind = "patch"
+	// IncPreMajor increments the major version and creates a pre-release: 1.2.3 -> 2.0.0-rc.0
+	IncPreMajor IncKind = "premajor"
+	// IncPreMinor increments the minor version and creates a pre-release: 1.2.3 -> 1.3.0-rc.0
+	IncPreMinor IncKind = "preminor"
+	// IncPrePatch increments the patch version and creates a pre-release: 1.2.3 -> 1.2.4-rc.0
+	IncPrePatch IncKind = "prepatch"
+	// IncPreRelease increments the counter of a pre-release (1.2.4-rc.0 -> 1.2.4-rc.1),
+	// or behaves as IncPrePatch for normal versions
+	IncPreRelease IncKind = "prerelease"
+)
+
+// IncOption configures how pre-releases are created when incrementing a version
+type IncOption func(c *incConfig)
+
+type incConfig struct {
+	id   string
+	base int64
+}
+
+// PreReleaseID sets the identifier used for pre-releases, such as "rc" or "beta". If not
+// provided, pre-releases only contain the numeric counter (1.2.3 -> 1.2.4-0)
+func PreReleaseID(id string) IncOption {
+	return func(c *incConfig) {
+		c.id = id
+	}
+}
+
+// PreReleaseCounterBase sets the initial value of the numeric counter of new pre-releases, 0 by default
+func PreReleaseCounterBase(n int64) IncOption {
+	return func(c *incConfig) {
+		c.base = n
+	}
+}
+
+// newPreRelease returns a pre-release string for a freshly created pre-release
+func (c *incConfig) newPreRelease() string {
+	counter := strconv.FormatInt(c.base, 10)
+	if c.id == "" {
+		return counter
+	}
+	return c.id + "." + counter
+}
+
+// nextPreRelease returns the pre-release following pr
+func (c *incConfig) nextPreRelease(pr string) string {
+	ids := strings.Split(pr, ".")
+	if c.id != "" && ids[0] != c.id {
+		return c.newPreRelease()
+	}
+	for i := len(ids) - 1; i >= 0; i-- {
+		if isNumeric(ids[i]) {
+			n, _ := strconv.ParseInt(ids[i], 10, 64)
+			ids[i] = strconv.FormatInt(n+1, 10)
+			return strings.Join(ids, ".")
+		}
+	}
+	return pr + "." + strconv.FormatInt(c.base, 10)
+}
+
+// Inc returns a new version resulting of applying the increment kind to v. Lower components
+// are reset and build metadata is dropped. The receiver is never modified
+func (v *Version) Inc(kind IncKind, opts ...IncOption) (*Version, error) {
+	c := &incConfig{}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.id != "" && !preReleaseIDRe.MatchString(c.id) {
+		return nil, fmt.Errorf("invalid pre-release identifier %q", c.id)
+	}
+	n := *v
+	n.majorPresent, n.minorPresent, n.patchPresent = true, true, true
+	n.Build = ""
+	switch kind {
+	case IncMajor:
+		if n.PreRelease == "" || n.Minor != 0 || n.Patch != 0 {
+			n.Major++
+		}
+		n.Minor, n.Patch, n.PreRelease = 0, 0, ""
+	case IncMinor:
+		if n.PreRelease == "" || n.Patch != 0 {
+			n.Minor++
+		}
+		n.Patch, n.PreRelease = 0, ""
+	case IncPatch:
+		if n.PreRelease == "" {
+			n.Patch++
+		}
+		n.PreRelease = ""
+	case IncPreMajor:
+		n.Major, n.Minor, n.Patch, n.PreRelease = n.Major+1, 0, 0, c.newPreRelease()
+	case IncPreMinor:
+		n.Minor, n.Patch, n.PreRelease = n.Minor+1, 0, c.newPreRelease()
+	case IncPrePatch:
+		n.Patch, n.PreRelease = n.Patch+1, c.newPreRelease()
+	case IncPreRelease:
+		if n.PreRelease == "" {
+			n.Patch, n.PreRelease = n.Patch+1, c.newPreRelease()
+		} else {
+			n.PreRelease = c.nextPreRelease(n.PreRelease)
+		}
+	default:
+		return nil, fmt.Errorf("unknown increment %q", kind)
+	}
+	return &n, nil
+}
+
+func (v *Version) mustInc(kind IncKind, opts ...IncOption) *Version {
+	n, err := v.Inc(kind, opts...)
+	if err != nil {
+		panic(err)
+	}
+	return n
+}
+
+// BumpMajor returns the next major version. It is equivalent to Inc(IncMajor)
+func (v *Version) BumpMajor() *Version {
+	return v.mustInc(IncMajor)
+}
+
+// BumpMinor returns the next minor version. It is equivalent to Inc(IncMinor)
+func (v *Version) BumpMinor() *Version {
+	return v.mustInc(IncMinor)
+}
+
+// BumpPatch returns the next patch version. It is equivalent to Inc(IncPatch)
+func (v *Version) BumpPatch() *Version {
+	return v.mustInc(IncPatch)
+}
+
+// BumpPreRelease returns the next pre-release version using the identifier id, which can be empty.
+// It is equivalent to Inc(IncPreRelease, PreReleaseID(id))
+func (v *Version) BumpPreRelease(id string) (*Version, error) {
+	return v.Inc(IncPreRelease, PreReleaseID(id))
+}
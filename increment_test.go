@@ -0,0 +1,98 @@
+package semver
+
+import "testing"
+
+type incTest struct {
+	v    string
+	kind IncKind
+}
+
+var rc = PreReleaseID("rc")
+
+var incTestBattery = map[incTest]string{
+	{v: "1.2.3", kind: IncMajor}:                   "2.0.0",
+	{v: "1.2.3-rc.1+b.1", kind: IncMajor}:          "2.0.0",
+	{v: "2.0.0-rc.1", kind: IncMajor}:              "2.0.0",
+	{v: "2.1.0-rc.1", kind: IncMajor}:              "3.0.0",
+	{v: "1.2.3+b.1", kind: IncMinor}:               "1.3.0",
+	{v: "1.3.0-rc.1", kind: IncMinor}:              "1.3.0",
+	{v: "1.3.1-rc.1", kind: IncMinor}:              "1.4.0",
+	{v: "1.2.3", kind: IncPatch}:                   "1.2.4",
+	{v: "1.2.4-rc.1", kind: IncPatch}:              "1.2.4",
+	{v: "1", kind: IncPatch}:                       "1.0.1",
+	{v: "1.2.3", kind: IncPreMajor}:                "2.0.0-0",
+	{v: "1.2.3-rc.4", kind: IncPreMinor}:           "1.3.0-0",
+	{v: "1.2.3+b.5", kind: IncPrePatch}:            "1.2.4-0",
+	{v: "1.2.3", kind: IncPreRelease}:              "1.2.4-0",
+	{v: "1.2.4-0", kind: IncPreRelease}:            "1.2.4-1",
+	{v: "1.2.4-alpha", kind: IncPreRelease}:        "1.2.4-alpha.0",
+	{v: "1.2.4-alpha.9", kind: IncPreRelease}:      "1.2.4-alpha.10",
+	{v: "1.2.4-alpha.1.beta", kind: IncPreRelease}: "1.2.4-alpha.2.beta",
+}
+
+func TestInc(t *testing.T) {
+	for test, expected := range incTestBattery {
+		v := MustParseVersion(test.v)
+		n, err := v.Inc(test.kind)
+		if err != nil || n.String() != expected {
+			t.Errorf("Expected Inc(%q) of %q to be %q but got %v (%v)", test.kind, test.v, expected, n, err)
+		}
+		if v.String() != MustParseVersion(test.v).String() {
+			t.Errorf("Expected Inc(%q) to not modify %q but got %q", test.kind, test.v, v)
+		}
+	}
+}
+
+func TestIncWithOptions(t *testing.T) {
+	for _, test := range []struct {
+		v        string
+		kind     IncKind
+		opts     []IncOption
+		expected string
+	}{
+		{v: "1.2.3", kind: IncPreMajor, opts: []IncOption{rc}, expected: "2.0.0-rc.0"},
+		{v: "1.2.3", kind: IncPreMinor, opts: []IncOption{rc, PreReleaseCounterBase(1)}, expected: "1.3.0-rc.1"},
+		{v: "1.2.3", kind: IncPrePatch, opts: []IncOption{PreReleaseID("beta")}, expected: "1.2.4-beta.0"},
+		{v: "1.2.3", kind: IncPreRelease, opts: []IncOption{rc}, expected: "1.2.4-rc.0"},
+		{v: "1.2.4-rc.1", kind: IncPreRelease, opts: []IncOption{rc}, expected: "1.2.4-rc.2"},
+		{v: "1.2.4-beta.3", kind: IncPreRelease, opts: []IncOption{rc}, expected: "1.2.4-rc.0"},
+		{v: "1.2.4-rc", kind: IncPreRelease, opts: []IncOption{rc, PreReleaseCounterBase(1)}, expected: "1.2.4-rc.1"},
+		{v: "1.2.4-rc.1", kind: IncPatch, opts: []IncOption{rc}, expected: "1.2.4"},
+	} {
+		n, err := MustParseVersion(test.v).Inc(test.kind, test.opts...)
+		if err != nil || n.String() != test.expected {
+			t.Errorf("Expected Inc(%q) of %q to be %q but got %v (%v)", test.kind, test.v, test.expected, n, err)
+		}
+	}
+}
+
+func TestIncErrors(t *testing.T) {
+	v := MustParseVersion("1.2.3")
+	if _, err := v.Inc("foo"); err == nil {
+		t.Errorf("Expected Inc to fail with an unknown increment")
+	}
+	for _, id := range []string{"rc.1", "a b", "é"} {
+		if _, err := v.Inc(IncPreRelease, PreReleaseID(id)); err == nil {
+			t.Errorf("Expected Inc to fail with pre-release identifier %q", id)
+		}
+	}
+}
+
+func TestBump(t *testing.T) {
+	v := MustParseVersion("1.2.3-rc.1+build")
+	for expected, res := range map[string]*Version{
+		"2.0.0": v.BumpMajor(),
+		"1.3.0": v.BumpMinor(),
+		"1.2.3": v.BumpPatch(),
+	} {
+		if res.String() != expected {
+			t.Errorf("Expected %q but got %q", expected, res)
+		}
+	}
+	if res, err := v.BumpPreRelease("rc"); err != nil || res.String() != "1.2.3-rc.2" {
+		t.Errorf("Expected %q but got %q (%v)", "1.2.3-rc.2", res, err)
+	}
+	if res, err := v.BumpPreRelease(""); err != nil || res.String() != "1.2.3-rc.2" {
+		t.Errorf("Expected %q but got %q (%v)", "1.2.3-rc.2", res, err)
+	}
+}
@@ -0,0 +1,276 @@
+package semver
+
+import (
+	"strings"
+)
+
+// emptyInterval is the interval notation of a range without versions, as nothing is lower than 0.0.0-0
+const emptyInterval = "(,0.0.0-0)"
+
+type intervalParser struct {
+	cargoParser
+}
+
+// intervalSyntax describes an interval as written in the input. Missing limits are empty
+type intervalSyntax struct {
+	min, max                   string
+	minOffset, maxOffset       int
+	minInclusive, maxInclusive bool
+	// bare is set for a version without brackets
+	bare     bool
+	operator string
+	location Span
+}
+
+// parseLimit returns the version delimited by a comma, a bracket or a space
+func (p *intervalParser) parseLimit() (string, int) {
+	start := p.pos
+	for p.pos < len(p.input) && strings.IndexByte(",[]() \t\n\r", p.input[p.pos]) < 0 {
+		p.pos++
+	}
+	return p.input[start:p.pos], start
+}
+
+// parseInterval parses an interval, such as "[1.0,2.0)", "(,1.5]" or "[1.2]", or a bare version
+func (p *intervalParser) parseInterval() (*intervalSyntax, error) {
+	p.skipSpaces()
+	i := &intervalSyntax{location: Span{Start: p.pos}}
+	open := p.peek()
+	if open != '[' && open != '(' {
+		i.min, i.minOffset = p.parseLimit()
+		if i.min == "" {
+			return nil, p.errorAt(p.pos, "version or interval")
+		}
+		i.bare, i.minInclusive, i.location.End = true, true, p.pos
+		return i, nil
+	}
+	p.pos++
+	p.skipSpaces()
+	i.min, i.minOffset = p.parseLimit()
+	i.minInclusive = open == '['
+	p.skipSpaces()
+	if i.min != "" && open == '[' && p.peek() == ']' {
+		// [1.2] only contains 1.2
+		p.pos++
+		i.max, i.maxOffset, i.maxInclusive = i.min, i.minOffset, true
+		i.operator, i.location.End = "[]", p.pos
+		return i, nil
+	}
+	if p.peek() != ',' {
+		return nil, p.errorAt(p.pos, `","`)
+	}
+	p.pos++
+	p.skipSpaces()
+	i.max, i.maxOffset = p.parseLimit()
+	p.skipSpaces()
+	closing := p.peek()
+	switch {
+	case closing != ']' && closing != ')':
+		return nil, p.errorAt(p.pos, `"]" or ")"`)
+	case closing == ']' && i.max == "":
+		// Missing limits are always exclusive
+		return nil, p.errorAt(p.pos, `")"`)
+	case open == '[' && i.min == "":
+		return nil, p.errorAt(i.location.Start, `"("`)
+	}
+	p.pos++
+	i.maxInclusive = closing == ']'
+	i.operator, i.location.End = string([]byte{open, closing}), p.pos
+	return i, nil
+}
+
+// parse parses a comma separated list of intervals
+func (p *intervalParser) parse() ([]*intervalSyntax, error) {
+	list := []*intervalSyntax{}
+	for {
+		i, err := p.parseInterval()
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, i)
+		p.skipSpaces()
+		switch {
+		case p.pos == len(p.input):
+			return list, nil
+		case p.peek() != ',':
+			return nil, p.errorAt(p.pos, `"," or end of input`)
+		}
+		p.pos++
+	}
+}
+
+// parseVersion parses a limit of an interval as a fixed version
+func (p *intervalParser) parseVersion(str string, offset int) (*GlobVersion, error) {
+	v, err := ParseVersion(str)
+	if err != nil {
+		return nil, p.errorAt(offset, "version")
+	}
+	return &GlobVersion{Version: NewVersion(v.Major, v.Minor, v.Patch, v.PreRelease, v.Build)}, nil
+}
+
+// toRangeNode returns a RangeNode with the limits of the interval. A bare version is a
+// minimum version, as in NuGet
+func (p *intervalParser) toRangeNode(i *intervalSyntax) (*RangeNode, error) {
+	r := &Range{AllowMinEquality: i.minInclusive, AllowMaxEquality: i.maxInclusive}
+	var err error
+	if i.min != "" {
+		if r.MinVersion, err = p.parseVersion(i.min, i.minOffset); err != nil {
+			return nil, err
+		}
+	}
+	if i.max != "" {
+		if r.MaxVersion, err = p.parseVersion(i.max, i.maxOffset); err != nil {
+			return nil, err
+		}
+	}
+	return &RangeNode{Range: r, Operator: i.operator, Location: i.location}, nil
+}
+
+// MustParseIntervals parses a list of intervals in the notation of Maven and NuGet
+// It panics if str is not well formed
+func MustParseIntervals(str string, opts ...ParseOption) Expression {
+	if e, err := ParseIntervals(str, opts...); err != nil {
+		panic(err)
+	} else {
+		return e
+	}
+}
+
+// ParseIntervals parses a comma separated union of intervals in the notation used by Maven and NuGet,
+// such as "(,1.0],[1.2,)". Square brackets include the limit and parentheses exclude it. Missing limits
+// are unbounded, "[1.2]" only contains 1.2 and a bare version such as "1.2" is a minimum version, as in
+// NuGet (Maven treats it as a soft requirement). Versions follow the syntax of ParseVersion, so partial
+// versions are completed with zeros: "[1.0,2.0)" is ">=1.0.0 <2.0.0".
+//
+// Each interval is mapped directly onto a Range, whose pre-releases follow the rules of ParseRange.
+// The returned Expression is an *Expr, whose syntax tree contains a RangeNode for each interval,
+// with its brackets as operator ("[)", "(]"...). Syntax errors are reported as a *ParseError
+func ParseIntervals(str string, opts ...ParseOption) (Expression, error) {
+	p := &intervalParser{cargoParser{input: str}}
+	list, err := p.parse()
+	if err != nil {
+		return nil, err
+	}
+	or := &OrNode{Location: Span{Start: list[0].location.Start, End: list[len(list)-1].location.End}}
+	for _, i := range list {
+		n, err := p.toRangeNode(i)
+		if err != nil {
+			return nil, err
+		}
+		or.Operands = append(or.Operands, n)
+	}
+	var root Node = or
+	if len(or.Operands) == 1 {
+		root = or.Operands[0]
+	}
+	e := &Expr{str: str, root: root}
+	for _, opt := range opts {
+		opt(&e.parseConfig)
+	}
+	Inspect(root, func(n Node) bool {
+		if n, ok := n.(*RangeNode); ok {
+			n.Range.IncludePrerelease = e.includePrerelease
+		}
+		return true
+	})
+	return e, nil
+}
+
+// MustParseIntervalRange parses a single interval in the notation of Maven and NuGet
+// It panics if str is not well formed
+func MustParseIntervalRange(str string, opts ...ParseOption) *Range {
+	r, err := ParseIntervalRange(str, opts...)
+	if err != nil {
+		panic(err)
+	}
+	return r
+}
+
+// ParseIntervalRange parses a single interval in the notation of Maven and NuGet, such as "[1.0,2.0)",
+// into a Range with the same limits. See ParseIntervals for the details of the syntax
+func ParseIntervalRange(str string, opts ...ParseOption) (*Range, error) {
+	p := &intervalParser{cargoParser{input: str}}
+	i, err := p.parseInterval()
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpaces()
+	if p.pos < len(p.input) {
+		return nil, p.errorAt(p.pos, "end of interval")
+	}
+	n, err := p.toRangeNode(i)
+	if err != nil {
+		return nil, err
+	}
+	var c parseConfig
+	for _, opt := range opts {
+		opt(&c)
+	}
+	n.Range.IncludePrerelease = c.includePrerelease
+	return n.Range, nil
+}
+
+// IntervalString returns the range in the interval notation of Maven and NuGet, such as "[1.2.0,2.0.0)".
+// Fixed limits are kept as they are, so the result is parsed by ParseIntervalRange into the same Range.
+// X-range limits are replaced with the equivalent fixed versions, taking into account IncludePrerelease,
+// which cannot be expressed in the notation. A range without versions is "(,0.0.0-0)"
+func (r *Range) IntervalString() string {
+	for _, limit := range []*GlobVersion{r.MinVersion, r.MaxVersion} {
+		if limit == nil || limit.IsFixed() {
+			continue
+		}
+		s, floor := r.versionSet(), lowestVersion()
+		if r.IncludePrerelease {
+			s, floor = r.exactVersionSet(), lowestPreRelease()
+		}
+		if len(s) == 0 {
+			return emptyInterval
+		}
+		fixed := &Range{AllowMinEquality: s[0].min.inclusive, AllowMaxEquality: s[0].max.inclusive}
+		if !s[0].min.inclusive || compareVersions(s[0].min.v, floor) != 0 {
+			fixed.MinVersion = &GlobVersion{Version: s[0].min.v}
+		}
+		if s[0].max.v != nil {
+			fixed.MaxVersion = &GlobVersion{Version: s[0].max.v}
+		}
+		return fixed.IntervalString()
+	}
+	if r.MinVersion != nil && r.MaxVersion != nil && r.AllowMinEquality && r.AllowMaxEquality &&
+		compareVersions(r.MinVersion.Version, r.MaxVersion.Version) == 0 {
+		return "[" + r.MinVersion.String() + "]"
+	}
+	str := "("
+	if r.MinVersion != nil {
+		if r.AllowMinEquality {
+			str = "["
+		}
+		str += r.MinVersion.String()
+	}
+	str += ","
+	if r.MaxVersion == nil {
+		return str + ")"
+	}
+	if r.AllowMaxEquality {
+		return str + r.MaxVersion.String() + "]"
+	}
+	return str + r.MaxVersion.String() + ")"
+}
+
+// FormatIntervals returns the release versions matched by e as a comma separated union of intervals
+// in the notation of Maven and NuGet, such as "[1.2.0,1.3.0),[2.0.0,)". It returns "(,0.0.0-0)"
+// if e does not match any version. As Canonical, it fails if e is not an expression returned by
+// ParseExpr or a range
+func FormatIntervals(e Expression) (string, error) {
+	s, err := setOf(e)
+	if err != nil {
+		return "", err
+	}
+	if len(s) == 0 {
+		return emptyInterval, nil
+	}
+	list := []string{}
+	for _, i := range s {
+		list = append(list, i.toRange().IntervalString())
+	}
+	return strings.Join(list, ","), nil
+}
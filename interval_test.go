@@ -0,0 +1,150 @@
+package semver
+
+import "testing"
+
+func TestParseIntervals(t *testing.T) {
+	for str, expected := range map[string]string{
+		"[1.0,2.0)":         ">=1.0.0 <2.0.0",
+		"[1.0,2.0]":         ">=1.0.0 <=2.0.0",
+		"(1.0,2.0)":         ">1.0.0 <2.0.0",
+		"(,1.5]":            "<=1.5.0",
+		"(,1.0)":            "<1.0.0",
+		"[1.5,)":            ">=1.5.0",
+		"(1.5,)":            ">1.5.0",
+		"[1.2]":             "1.2.0",
+		"1.2":               ">=1.2.0",
+		"(,)":               "*",
+		"(,1.0],[1.2,)":     "<=1.0.0 || >=1.2.0",
+		"(,1.1),(1.1,)":     "<1.1.0 || >1.1.0",
+		" [ 1.0 , 2.0 ) ":   ">=1.0.0 <2.0.0",
+		"[1.2.3-beta,2.0)":  ">=1.2.3-beta <2.0.0",
+		"[1.0,2.0),[1.5,3)": ">=1.0.0 <3.0.0",
+		"(,0.0.0-0)":        "<0.0.0",
+		"[2.0,1.0]":         "<0.0.0",
+	} {
+		e, err := ParseIntervals(str)
+		if err != nil {
+			t.Errorf("Expected %q to be a valid list of intervals but got %v", str, err)
+			continue
+		}
+		if res, err := Canonical(e); err != nil || res != expected {
+			t.Errorf("Expected intervals %q to be %q but got %q (%v)", str, expected, res, err)
+		}
+		if e.String() != str {
+			t.Errorf("Expected the string of %q to be the original input but got %q", str, e)
+		}
+	}
+}
+
+func TestParseIntervalsErrors(t *testing.T) {
+	for str, offset := range map[string]int{
+		"":             0,
+		"[1.0,2.0":     8,
+		"[1.0 2.0)":    5,
+		"(1.0]":        4,
+		"[,1.0]":       0,
+		"[1.0,]":       5,
+		"[1.0,2.0)x":   9,
+		"[1.0,2.0),":   10,
+		"[junk,2.0)":   1,
+		"[1.0,2.0);":   9,
+		"[1.0,2.0]]":   9,
+		"(,1.0],,[2,)": 7,
+	} {
+		_, err := ParseIntervals(str)
+		if pErr, ok := err.(*ParseError); !ok || pErr.Offset != offset {
+			t.Errorf("Expected intervals %q to fail at offset %d but got %v", str, offset, err)
+		}
+	}
+	if _, err := ParseIntervalRange("(,1.0],[1.2,)"); err == nil {
+		t.Errorf("Expected ParseIntervalRange to reject unions")
+	}
+}
+
+func TestParseIntervalRange(t *testing.T) {
+	r := MustParseIntervalRange("(1.0,2.0.0-beta]", IncludePrerelease)
+	if !r.MinVersion.Equal(MustParseVersion("1.0.0")) || r.AllowMinEquality ||
+		!r.MaxVersion.Equal(MustParseVersion("2.0.0-beta")) || !r.AllowMaxEquality || !r.IncludePrerelease {
+		t.Errorf("Expected the limits of the interval to be mapped onto the range but got %#v", r)
+	}
+	if !r.Contains(MustParseVersion("1.5.0-rc.1")) || r.Contains(MustParseVersion("2.0.0")) {
+		t.Errorf("Expected %q to contain the pre-releases within its limits", r.IntervalString())
+	}
+	e := MustParseIntervals("[1.0,2.0.0-beta]")
+	if e.Matches(MustParseVersion("1.5.0-rc.1")) || !e.Matches(MustParseVersion("2.0.0-alpha")) {
+		t.Errorf("Expected intervals to follow the pre-release rules of ParseRange")
+	}
+}
+
+func TestRangeIntervalString(t *testing.T) {
+	for str, expected := range map[string]string{
+		"1.2.3":           "[1.2.3]",
+		">=1.2.3":         "[1.2.3,)",
+		"^1.2.3":          "[1.2.3,2.0.0)",
+		"~1.2":            "[1.2.0,1.3.0)",
+		"1.x":             "[1.0.0,2.0.0)",
+		"*":               "(,)",
+		">1.2.3-beta":     "(1.2.3-beta,)",
+		"<=1.0.0":         "(,1.0.0]",
+		"1.2.3 - 1.4":     "[1.2.3,1.5.0)",
+		"<0.0.0":          "(,0.0.0)",
+		">1.x":            "[2.0.0,)",
+		"<x":              "(,0.0.0-0)",
+		">=1.0.0+build.1": "[1.0.0+build.1,)",
+	} {
+		r := MustParseRange(str)
+		res := r.IntervalString()
+		if res != expected {
+			t.Errorf("Expected range %q to be %q in interval notation but got %q", str, expected, res)
+			continue
+		}
+		if r2 := MustParseIntervalRange(res); r2.String() != r.String() {
+			t.Errorf("Expected interval %q to be equivalent to range %q but got %q", res, str, r2)
+		}
+	}
+	if res := MustParseRange("1.x", IncludePrerelease).IntervalString(); res != "[1.0.0-0,2.0.0-0)" {
+		t.Errorf("Expected IncludePrerelease to be taken into account by IntervalString but got %q", res)
+	}
+}
+
+func TestIntervalRoundTrip(t *testing.T) {
+	versions := []string{
+		"0.0.0", "0.0.0-0", "0.9.9", "1.0.0-alpha", "1.0.0", "1.2.3-beta", "1.2.3", "1.2.4",
+		"1.5.0-rc.1", "1.9.9", "2.0.0-0", "2.0.0-beta", "2.0.0", "2.0.1", "3.0.0",
+	}
+	for _, str := range []string{
+		"1.2.3", "^1.2.3", "~1.2", "1.x", "*", ">1.2.3-beta", "<=2.0.0-beta", "1.2.3 - 2",
+		">=1.2.3-beta", "<2", ">1.2", "<=1.x", ">=0.0.0-0",
+	} {
+		for _, opts := range [][]ParseOption{nil, {IncludePrerelease}} {
+			r := MustParseRange(str, opts...)
+			r2 := MustParseIntervalRange(r.IntervalString(), opts...)
+			for _, v := range versions {
+				if r.Contains(MustParseVersion(v)) != r2.Contains(MustParseVersion(v)) {
+					t.Errorf("Expected %q (%d options) and %q to agree on %q", str, len(opts), r.IntervalString(), v)
+				}
+			}
+		}
+	}
+}
+
+func TestFormatIntervals(t *testing.T) {
+	for expr, expected := range map[string]string{
+		"^1.2 || ^3":          "[1.2.0,2.0.0),[3.0.0,4.0.0)",
+		"<1.0.0 || >=1.2.0":   "(,1.0.0),[1.2.0,)",
+		"1.2.3 || 1.2.4":      "[1.2.3],[1.2.4]",
+		">2 <1":               "(,0.0.0-0)",
+		"*":                   "(,)",
+		"!1.5.0 && ^1.0":      "[1.0.0,1.5.0),(1.5.0,2.0.0)",
+		">=1.2.3-beta <2.0.0": "[1.2.3-beta,2.0.0)",
+	} {
+		res, err := FormatIntervals(MustParseExpr(expr))
+		if err != nil || res != expected {
+			t.Errorf("Expected %q to be formatted as intervals %q but got %q (%v)", expr, expected, res, err)
+			continue
+		}
+		if ok, err := Equivalent(MustParseIntervals(res), MustParseExpr(expr)); err != nil || !ok {
+			t.Errorf("Expected intervals %q to be equivalent to %q", res, expr)
+		}
+	}
+}
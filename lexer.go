@@ -0,0 +1,130 @@
+package semver
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+type tokenType int
+
+const (
+	tokenEOF tokenType = iota
+	tokenVersion
+	tokenOperator
+	tokenHyphen
+	tokenOr
+	tokenAnd
+	tokenNot
+	tokenLParen
+	tokenRParen
+)
+
+// expectedTerm describes the tokens that can start a term of an expression
+const expectedTerm = `version, range operator, "!" or "("`
+
+// token defines a lexical element of an expression, located at the byte offset pos of the input
+type token struct {
+	typ  tokenType
+	text string
+	pos  int
+}
+
+// ParseError describes a problem found while parsing an expression
+type ParseError struct {
+	// Input is the string being parsed
+	Input string
+	// Offset is the byte offset of the offending token in Input
+	Offset int
+	// Token is the offending text. It is empty if the input ended unexpectedly
+	Token string
+	// Expected describes what was expected instead of Token
+	Expected string
+}
+
+func (e *ParseError) Error() string {
+	found := "end of input"
+	if e.Token != "" {
+		found = fmt.Sprintf("%q", e.Token)
+	}
+	return fmt.Sprintf("unexpected %s at offset %d in expression %q: expected %s", found, e.Offset, e.Input, e.Expected)
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
+
+func isVersionStart(c byte) bool {
+	return (c >= '0' && c <= '9') || strings.IndexByte("vxX*", c) >= 0
+}
+
+func isVersionChar(c byte) bool {
+	return (c >= '0' && c <= '9') || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || strings.IndexByte(".*+-", c) >= 0
+}
+
+// lex splits the expression str into tokens. The last token is always a tokenEOF
+func lex(str string) ([]token, error) {
+	tokens := []token{}
+	for i := 0; i < len(str); {
+		c := str[i]
+		start := i
+		switch {
+		case isSpace(c):
+			i++
+			continue
+		case c == '|':
+			if i+1 >= len(str) || str[i+1] != '|' {
+				return nil, &ParseError{Input: str, Offset: i, Token: str[i : i+1], Expected: `"||"`}
+			}
+			i += 2
+			tokens = append(tokens, token{typ: tokenOr, text: str[start:i], pos: start})
+		case c == '&':
+			if i+1 >= len(str) || str[i+1] != '&' {
+				return nil, &ParseError{Input: str, Offset: i, Token: str[i : i+1], Expected: `"&&"`}
+			}
+			i += 2
+			tokens = append(tokens, token{typ: tokenAnd, text: str[start:i], pos: start})
+		case c == '!':
+			i++
+			if i < len(str) && str[i] == '=' {
+				i++
+				tokens = append(tokens, token{typ: tokenOperator, text: str[start:i], pos: start})
+			} else {
+				tokens = append(tokens, token{typ: tokenNot, text: str[start:i], pos: start})
+			}
+		case c == '(':
+			i++
+			tokens = append(tokens, token{typ: tokenLParen, text: str[start:i], pos: start})
+		case c == ')':
+			i++
+			tokens = append(tokens, token{typ: tokenRParen, text: str[start:i], pos: start})
+		case c == '-':
+			i++
+			tokens = append(tokens, token{typ: tokenHyphen, text: str[start:i], pos: start})
+		case c == '^' || c == '=':
+			i++
+			tokens = append(tokens, token{typ: tokenOperator, text: str[start:i], pos: start})
+		case c == '~':
+			i++
+			if i < len(str) && str[i] == '>' {
+				i++
+			}
+			tokens = append(tokens, token{typ: tokenOperator, text: str[start:i], pos: start})
+		case c == '<' || c == '>':
+			i++
+			if i < len(str) && str[i] == '=' {
+				i++
+			}
+			tokens = append(tokens, token{typ: tokenOperator, text: str[start:i], pos: start})
+		case isVersionStart(c):
+			for i < len(str) && isVersionChar(str[i]) {
+				i++
+			}
+			tokens = append(tokens, token{typ: tokenVersion, text: str[start:i], pos: start})
+		default:
+			_, size := utf8.DecodeRuneInString(str[i:])
+			return nil, &ParseError{Input: str, Offset: i, Token: str[i : i+size], Expected: expectedTerm}
+		}
+	}
+	return append(tokens, token{typ: tokenEOF, pos: len(str)}), nil
+}
@@ -0,0 +1,403 @@
+package semver
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// mavenQualifiers contains the well known qualifiers in ascending order. The empty qualifier is a release
+var mavenQualifiers = []string{"alpha", "beta", "milestone", "rc", "snapshot", "", "sp"}
+
+var mavenAliases = map[string]string{"ga": "", "final": "", "release": "", "cr": "rc"}
+
+// mavenReleaseIndex is the comparable form of the empty qualifier
+var mavenReleaseIndex = mavenComparableQualifier("")
+
+// mavenComparableQualifier returns a string that sorts qualifiers as Maven does: well known
+// qualifiers by their position and unknown ones after them, in lexical order
+func mavenComparableQualifier(q string) string {
+	for i, known := range mavenQualifiers {
+		if q == known {
+			return strconv.Itoa(i)
+		}
+	}
+	return fmt.Sprintf("%d-%s", len(mavenQualifiers), q)
+}
+
+type mavenItemKind int
+
+const (
+	mavenInt mavenItemKind = iota
+	mavenString
+	mavenList
+)
+
+// mavenItem is an item of a Maven version: a number, a qualifier or a list of items. Numbers can
+// have any length, so they are kept as digits without leading zeros
+type mavenItem struct {
+	kind  mavenItemKind
+	value string
+	items []*mavenItem
+}
+
+func newMavenInt(digits string) *mavenItem {
+	return &mavenItem{kind: mavenInt, value: trimDigits(digits)}
+}
+
+// newMavenString returns a qualifier item. Single letters followed by a number are
+// abbreviations: "a1" is "alpha-1", "b1" is "beta-1" and "m1" is "milestone-1"
+func newMavenString(value string, followedByDigit bool) *mavenItem {
+	if followedByDigit && len(value) == 1 {
+		switch value {
+		case "a":
+			value = "alpha"
+		case "b":
+			value = "beta"
+		case "m":
+			value = "milestone"
+		}
+	}
+	if alias, ok := mavenAliases[value]; ok {
+		value = alias
+	}
+	return &mavenItem{kind: mavenString, value: value}
+}
+
+func newMavenItem(isDigit bool, str string) *mavenItem {
+	if isDigit {
+		return newMavenInt(str)
+	}
+	return newMavenString(str, false)
+}
+
+// isNull returns true if the item does not affect the ordering when it is the last one
+func (i *mavenItem) isNull() bool {
+	switch i.kind {
+	case mavenInt:
+		return i.value == "0"
+	case mavenString:
+		return mavenComparableQualifier(i.value) == mavenReleaseIndex
+	default:
+		return len(i.items) == 0
+	}
+}
+
+// normalize removes the trailing null items of a list, such as the zeros of "1.0.0"
+func (i *mavenItem) normalize() {
+	for idx := len(i.items) - 1; idx >= 0; idx-- {
+		if last := i.items[idx]; last.isNull() {
+			i.items = append(i.items[:idx], i.items[idx+1:]...)
+		} else if last.kind != mavenList {
+			break
+		}
+	}
+}
+
+// compare compares the item with i2, which is nil if the other version has no more items
+func (i *mavenItem) compare(i2 *mavenItem) int {
+	switch i.kind {
+	case mavenInt:
+		switch {
+		case i2 == nil:
+			if i.value == "0" {
+				return 0
+			}
+			return 1
+		case i2.kind == mavenInt:
+			return compareDigits(i.value, i2.value)
+		default:
+			// 1.1 > 1-sp and 1.1 > 1-1
+			return 1
+		}
+	case mavenString:
+		switch {
+		case i2 == nil:
+			return strings.Compare(mavenComparableQualifier(i.value), mavenReleaseIndex)
+		case i2.kind == mavenString:
+			return strings.Compare(mavenComparableQualifier(i.value), mavenComparableQualifier(i2.value))
+		default:
+			return -1
+		}
+	default:
+		switch {
+		case i2 == nil:
+			for _, item := range i.items {
+				if res := item.compare(nil); res != 0 {
+					return res
+				}
+			}
+			return 0
+		case i2.kind == mavenInt:
+			return -1
+		case i2.kind == mavenString:
+			return 1
+		}
+		for idx := 0; idx < len(i.items) || idx < len(i2.items); idx++ {
+			var l, r *mavenItem
+			if idx < len(i.items) {
+				l = i.items[idx]
+			}
+			if idx < len(i2.items) {
+				r = i2.items[idx]
+			}
+			res := 0
+			switch {
+			case l != nil:
+				res = l.compare(r)
+			case r != nil:
+				res = -r.compare(nil)
+			}
+			if res != 0 {
+				return res
+			}
+		}
+		return 0
+	}
+}
+
+func (i *mavenItem) String() string {
+	if i.kind != mavenList {
+		return i.value
+	}
+	str := ""
+	for idx, item := range i.items {
+		if idx > 0 {
+			if item.kind == mavenList {
+				str += "-"
+			} else {
+				str += "."
+			}
+		}
+		str += item.String()
+	}
+	return str
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+// parseMavenItems splits a version into items. Dots separate items, while hyphens and transitions
+// between digits and letters start a new sublist: "1.0-alpha2" is [1, [alpha, [2]]]
+func parseMavenItems(str string) *mavenItem {
+	str = strings.ToLower(str)
+	list := &mavenItem{kind: mavenList}
+	stack := []*mavenItem{list}
+	sublist := func() {
+		l := &mavenItem{kind: mavenList}
+		list.items = append(list.items, l)
+		list = l
+		stack = append(stack, l)
+	}
+	digits, start := false, 0
+	for i := 0; i < len(str); i++ {
+		switch c := str[i]; {
+		case c == '.' || c == '-':
+			if i == start {
+				list.items = append(list.items, newMavenInt("0"))
+			} else {
+				list.items = append(list.items, newMavenItem(digits, str[start:i]))
+			}
+			digits, start = false, i+1
+			if c == '-' {
+				sublist()
+			}
+		case isDigit(c):
+			if !digits && i > start {
+				list.items = append(list.items, newMavenString(str[start:i], true))
+				start = i
+				sublist()
+			}
+			digits = true
+		default:
+			if digits && i > start {
+				list.items = append(list.items, newMavenInt(str[start:i]))
+				start = i
+				sublist()
+			}
+			digits = false
+		}
+	}
+	if len(str) > start {
+		list.items = append(list.items, newMavenItem(digits, str[start:]))
+	}
+	for i := len(stack) - 1; i >= 0; i-- {
+		stack[i].normalize()
+	}
+	return stack[0]
+}
+
+// MavenVersion describes a version of a Maven artifact, such as "1.0-SNAPSHOT", "2.0-M1" or
+// "1.0.0.Final". Maven versions are not required to follow SemVer, and they are ordered following
+// the ComparableVersion algorithm of Maven
+type MavenVersion struct {
+	str   string
+	items *mavenItem
+}
+
+// MustParseMavenVersion parses a Maven version
+// It panics if it cannot be parsed
+func MustParseMavenVersion(str string) *MavenVersion {
+	if v, err := ParseMavenVersion(str); err != nil {
+		panic(err)
+	} else {
+		return v
+	}
+}
+
+// ParseMavenVersion parses a Maven version. Any string is a valid Maven version, as long as
+// it is not empty and it does not contain spaces
+func ParseMavenVersion(str string) (*MavenVersion, error) {
+	if str == "" || strings.IndexAny(str, " \t\n\r") >= 0 {
+		return nil, fmt.Errorf("malformed Maven version string %q", str)
+	}
+	return &MavenVersion{str: str, items: parseMavenItems(str)}, nil
+}
+
+// mavenVersionOf returns the Maven version equivalent to the semver v, ignoring its build metadata
+func mavenVersionOf(v *Version) *MavenVersion {
+	str := fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+	if v.PreRelease != "" {
+		str += "-" + v.PreRelease
+	}
+	return MustParseMavenVersion(str)
+}
+
+func (v *MavenVersion) String() string {
+	return v.str
+}
+
+// Canonical returns the normalized form of the version, which is the same for equal versions:
+// "1.0.0.Final" and "1-GA" are "1", and "2.0-M1" is "2-milestone-1"
+func (v *MavenVersion) Canonical() string {
+	return v.items.String()
+}
+
+// Compare returns -1, 0 or 1 if v is respectively lower, equal or higher than v2. Items are compared
+// one by one: numbers numerically and qualifiers in the order alpha < beta < milestone < rc < snapshot
+// < "" (release, ga or final) < sp, unknown qualifiers being higher and compared lexically. Numbers
+// are higher than qualifiers, so "1-SNAPSHOT" < "1" < "1-sp" < "1.1"
+func (v *MavenVersion) Compare(v2 *MavenVersion) int {
+	return v.items.compare(v2.items)
+}
+
+// MavenVersions defines a list of Maven versions sortable in ascending order. It implements sort.Interface
+type MavenVersions []*MavenVersion
+
+func (vs MavenVersions) Len() int           { return len(vs) }
+func (vs MavenVersions) Swap(i, j int)      { vs[i], vs[j] = vs[j], vs[i] }
+func (vs MavenVersions) Less(i, j int) bool { return vs[i].Compare(vs[j]) < 0 }
+
+// SortMavenVersions sorts the Maven versions in ascending order. Equal versions, such as "1.0"
+// and "1.0.0.Final", keep their relative order
+func SortMavenVersions(versions []*MavenVersion) {
+	sort.Stable(MavenVersions(versions))
+}
+
+// compareMavenQualifiers compares two pre-releases as Maven qualifiers
+func compareMavenQualifiers(pr1, pr2 string) (int, error) {
+	qualified := func(pr string) *mavenItem {
+		if pr == "" {
+			return parseMavenItems("0")
+		}
+		return parseMavenItems("0-" + pr)
+	}
+	return qualified(pr1).compare(qualified(pr2)), nil
+}
+
+// mavenRestriction is an interval of Maven versions. Missing limits are nil
+type mavenRestriction struct {
+	min, max                   *MavenVersion
+	minInclusive, maxInclusive bool
+}
+
+func (r mavenRestriction) contains(v *MavenVersion) bool {
+	if r.min != nil {
+		if res := v.Compare(r.min); res < 0 || (res == 0 && !r.minInclusive) {
+			return false
+		}
+	}
+	if r.max != nil {
+		if res := v.Compare(r.max); res > 0 || (res == 0 && !r.maxInclusive) {
+			return false
+		}
+	}
+	return true
+}
+
+// MavenVersionRange describes a Maven version range, such as "[1.0,2.0)" or "(,1.0],[1.2,)",
+// whose limits are Maven versions
+type MavenVersionRange struct {
+	str          string
+	restrictions []mavenRestriction
+	recommended  *MavenVersion
+}
+
+// MustParseMavenVersionRange parses a Maven version range
+// It panics if it cannot be parsed
+func MustParseMavenVersionRange(str string) *MavenVersionRange {
+	if r, err := ParseMavenVersionRange(str); err != nil {
+		panic(err)
+	} else {
+		return r
+	}
+}
+
+// ParseMavenVersionRange parses a Maven version range, using the interval notation described in
+// ParseIntervals. Unlike in ParseIntervals, limits are Maven versions and a bare version, such as "1.0",
+// is a soft requirement: it matches any version, and it is returned by Recommended.
+// Syntax errors are reported as a *ParseError
+func ParseMavenVersionRange(str string) (*MavenVersionRange, error) {
+	p := &intervalParser{cargoParser{input: str}}
+	list, err := p.parse()
+	if err != nil {
+		return nil, err
+	}
+	r := &MavenVersionRange{str: str}
+	for _, i := range list {
+		if i.bare {
+			if len(list) > 1 {
+				return nil, p.errorAt(i.location.Start, "interval")
+			}
+			r.recommended = MustParseMavenVersion(i.min)
+			r.restrictions = []mavenRestriction{{}}
+			break
+		}
+		restriction := mavenRestriction{minInclusive: i.minInclusive, maxInclusive: i.maxInclusive}
+		if i.min != "" {
+			restriction.min = MustParseMavenVersion(i.min)
+		}
+		if i.max != "" {
+			restriction.max = MustParseMavenVersion(i.max)
+		}
+		r.restrictions = append(r.restrictions, restriction)
+	}
+	return r, nil
+}
+
+func (r *MavenVersionRange) String() string {
+	return r.str
+}
+
+// Recommended returns the version of a soft requirement, or nil if the range is made of intervals
+func (r *MavenVersionRange) Recommended() *MavenVersion {
+	return r.recommended
+}
+
+// ContainsVersion checks if the Maven version v is contained in any of the intervals of the range
+func (r *MavenVersionRange) ContainsVersion(v *MavenVersion) bool {
+	for _, restriction := range r.restrictions {
+		if restriction.contains(v) {
+			return true
+		}
+	}
+	return false
+}
+
+// Matches checks if the range contains the Maven version equivalent to v, so that a MavenVersionRange
+// can be used as an Expression. Following Maven, pre-releases within the limits are contained
+func (r *MavenVersionRange) Matches(v *Version) bool {
+	return r.ContainsVersion(mavenVersionOf(v))
+}
@@ -0,0 +1,153 @@
+package semver
+
+import "testing"
+
+// Ordered lists from the test suite of Maven's ComparableVersion
+var mavenOrderedVersions = [][]string{
+	{
+		"1-alpha2snapshot", "1-alpha2", "1-alpha-123", "1-beta-2", "1-beta123", "1-m2", "1-m11", "1-rc", "1-cr2",
+		"1-rc123", "1-SNAPSHOT", "1", "1-sp", "1-sp2", "1-sp123", "1-abc", "1-def", "1-pom-1", "1-1-snapshot",
+		"1-1", "1-2", "1-123",
+	},
+	{
+		"2.0", "2-1", "2.0.a", "2.0.0.a", "2.0.2", "2.0.123", "2.1.0", "2.1-a", "2.1b", "2.1-c", "2.1-1",
+		"2.1.0.1", "2.2", "2.123", "11.a2", "11.a11", "11.b2", "11.b11", "11.m2", "11.m11", "11", "11.a",
+		"11b", "11c", "11m",
+	},
+}
+
+func TestMavenVersionOrdering(t *testing.T) {
+	for _, list := range mavenOrderedVersions {
+		for i, str := range list {
+			v := MustParseMavenVersion(str)
+			for j, str2 := range list {
+				expected := compareInt(i, j)
+				if res := v.Compare(MustParseMavenVersion(str2)); res != expected {
+					t.Errorf("Expected the comparison of Maven versions %q and %q to be %d but got %d", str, str2, expected, res)
+				}
+			}
+		}
+	}
+}
+
+func TestMavenVersionEquality(t *testing.T) {
+	for _, list := range [][]string{
+		{"1", "1.0", "1.0.0", "1-0", "1.0-0", "1-ga", "1-GA", "1-final", "1-release", "1.0.0.Final", "1.0.0-RELEASE"},
+		{"1a", "1-a", "1.0-a", "1.0.0-a", "1-a-0"},
+		{"1x", "1-x", "1.0.0-x"},
+		{"1cr", "1rc", "1-cr", "1-rc", "1.0.0-CR"},
+		{"1a1", "1-alpha-1", "1.0alpha1", "1-ALPHA1"},
+		{"1b2", "1-beta-2", "1.0.0-beta2"},
+		{"1m3", "1-milestone-3", "1.0.0-M3"},
+		{"1.0.0.00100", "1.0.0.100", "1.0.0.0100"},
+	} {
+		for _, str := range list {
+			for _, str2 := range list {
+				if res := MustParseMavenVersion(str).Compare(MustParseMavenVersion(str2)); res != 0 {
+					t.Errorf("Expected Maven versions %q and %q to be equal but got %d", str, str2, res)
+				}
+			}
+		}
+	}
+}
+
+func TestMavenVersionCanonical(t *testing.T) {
+	for str, expected := range map[string]string{
+		"1.0.0.Final":                      "1",
+		"1-GA":                             "1",
+		"2.0-M1":                           "2-milestone-1",
+		"1.0-alpha-1":                      "1-alpha-1",
+		"1.0-SNAPSHOT":                     "1-snapshot",
+		"1.2.3":                            "1.2.3",
+		"1.0.0-beta.2":                     "1-beta.2",
+		"01.02-sp1":                        "1.2-sp-1",
+		"123456789012345678901234567890.1": "123456789012345678901234567890.1",
+	} {
+		if res := MustParseMavenVersion(str).Canonical(); res != expected {
+			t.Errorf("Expected the canonical form of Maven version %q to be %q but got %q", str, expected, res)
+		}
+	}
+	for _, str := range []string{"", "1.0 final", " 1.0"} {
+		if _, err := ParseMavenVersion(str); err == nil {
+			t.Errorf("Expected Maven version %q to be invalid", str)
+		}
+	}
+}
+
+func TestSortMavenVersions(t *testing.T) {
+	list := mavenOrderedVersions[1]
+	versions := []*MavenVersion{}
+	for i := len(list) - 1; i >= 0; i-- {
+		versions = append(versions, MustParseMavenVersion(list[i]))
+	}
+	SortMavenVersions(versions)
+	for i, v := range versions {
+		if v.String() != list[i] {
+			t.Errorf("Expected Maven version %d to be %q but got %q", i, list[i], v)
+		}
+	}
+}
+
+func TestMavenVersionRange(t *testing.T) {
+	for str, data := range map[string]map[string]bool{
+		"[1.0,2.0)":     {"1.0": true, "1.0.0.Final": true, "1.5-SNAPSHOT": true, "2.0-SNAPSHOT": true, "2.0": false, "1.0-rc1": false},
+		"(,1.0],[1.2,)": {"0.9": true, "1.0": true, "1.0-sp1": false, "1.1": false, "1.2-beta": false, "1.2": true},
+		"[1.2]":         {"1.2": true, "1.2.0": true, "1.2.1": false},
+		"1.0":           {"0.1": true, "3.0": true},
+		"(1.0-alpha,)":  {"1.0-beta": true, "1.0-alpha": false},
+	} {
+		r := MustParseMavenVersionRange(str)
+		for v, expected := range data {
+			if r.ContainsVersion(MustParseMavenVersion(v)) != expected {
+				t.Errorf("Expected Maven range %q to contain %q: %v", str, v, expected)
+			}
+		}
+	}
+	if r := MustParseMavenVersionRange("1.0"); r.Recommended() == nil || r.Recommended().String() != "1.0" {
+		t.Errorf("Expected a bare version to be the recommended version of a soft requirement")
+	}
+	if r := MustParseMavenVersionRange("[1.0,)"); r.Recommended() != nil {
+		t.Errorf("Expected ranges with intervals to have no recommended version")
+	}
+	var e Expression = MustParseMavenVersionRange("[1.0,2.0)")
+	if !e.Matches(MustParseVersion("1.5.0-rc.1")) || e.Matches(MustParseVersion("2.0.0")) {
+		t.Errorf("Expected Maven ranges to match the equivalent SemVer versions")
+	}
+	for str, offset := range map[string]int{"[1.0,2.0": 8, "1.0,[2.0,)": 0, "[1.0,2.0),": 10} {
+		_, err := ParseMavenVersionRange(str)
+		if pErr, ok := err.(*ParseError); !ok || pErr.Offset != offset {
+			t.Errorf("Expected Maven range %q to fail at offset %d but got %v", str, offset, err)
+		}
+	}
+}
+
+func TestMavenPreReleasesHack(t *testing.T) {
+	for _, test := range []struct {
+		v1, v2   string
+		expected int
+	}{
+		{"1.0.0-SNAPSHOT", "1.0.0", -1},
+		{"1.0.0-sp1", "1.0.0", 1},
+		{"1.0.0-rc1", "1.0.0-SNAPSHOT", -1},
+		{"1.0.0-alpha-1", "1.0.0-beta-1", -1},
+		{"1.0.0-M2", "1.0.0-beta10", 1},
+		{"1.0.0-Final", "1.0.0", 0},
+	} {
+		v1 := MustParseVersion(test.v1).Hack(MavenPreReleases)
+		v2 := MustParseVersion(test.v2)
+		var res int
+		switch {
+		case v1.Greater(v2):
+			res = 1
+		case v1.Less(v2):
+			res = -1
+		}
+		if res != test.expected {
+			t.Errorf("Expected the comparison of %q and %q to be %d with Maven pre-releases but got %d", test.v1, test.v2, test.expected, res)
+		}
+	}
+	r := MustParseRange(">=1.0.0", IncludePrerelease)
+	if r.Contains(MustParseVersion("1.0.0-SNAPSHOT").Hack(MavenPreReleases)) || !r.Contains(MustParseVersion("1.0.0-sp1").Hack(MavenPreReleases)) {
+		t.Errorf("Expected ranges to honor the ordering of Maven pre-releases")
+	}
+}
@@ -0,0 +1,211 @@
+// Package mvs implements Minimal Version Selection, the algorithm used by Go modules to compute
+// the build list of a module: among the versions required by the modules of a dependency graph,
+// the highest minimum version of each module is selected. Versions are ordered following the
+// SemVer precedence rules of the semver package.
+//
+// Dependency graphs are read through the Graph interface. MemoryRegistry and DirRegistry
+// implement it with requirements kept in memory or stored in a directory.
+package mvs
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/juamedgod/semver"
+)
+
+// Module identifies a version of a module
+type Module struct {
+	Path    string
+	Version *semver.Version
+}
+
+// MustParseModule parses a module version written as "path@version"
+// It panics if it cannot be parsed
+func MustParseModule(str string) Module {
+	if m, err := ParseModule(str); err != nil {
+		panic(err)
+	} else {
+		return m
+	}
+}
+
+// ParseModule parses a module version written as "path@version", such as "example.com/mod@1.2.0"
+func ParseModule(str string) (Module, error) {
+	i := strings.LastIndex(str, "@")
+	if i <= 0 {
+		return Module{}, fmt.Errorf("malformed module %q: expected path@version", str)
+	}
+	v, err := semver.ParseVersion(str[i+1:])
+	if err != nil {
+		return Module{}, fmt.Errorf("malformed module %q: %v", str, err)
+	}
+	return Module{Path: str[:i], Version: v}, nil
+}
+
+func (m Module) String() string {
+	return m.Path + "@" + m.Version.String()
+}
+
+// Graph describes a dependency graph, in which each version of a module requires minimum
+// versions of other modules
+type Graph interface {
+	// Required returns the minimum versions of the modules required by m
+	Required(m Module) ([]Module, error)
+	// Versions returns the available versions of the module with the given path, in ascending order.
+	// It is only used to look for older versions by Downgrade
+	Versions(path string) ([]*semver.Version, error)
+}
+
+// byPath sorts modules by path. It implements sort.Interface
+type byPath []Module
+
+func (ms byPath) Len() int           { return len(ms) }
+func (ms byPath) Swap(i, j int)      { ms[i], ms[j] = ms[j], ms[i] }
+func (ms byPath) Less(i, j int) bool { return ms[i].Path < ms[j].Path }
+
+// BuildList returns the build list of target: the target itself followed by the selected version
+// of each module it depends on, sorted by path. The selected version of a module is the highest of
+// the minimum versions required through the graph, and only modules still required when following
+// the selected versions are listed. The version of target is always selected for its path
+func BuildList(target Module, g Graph) ([]Module, error) {
+	required, err := g.Required(target)
+	if err != nil {
+		return nil, err
+	}
+	return buildList(target, required, g)
+}
+
+// buildList returns the build list of target, using required as its requirements
+func buildList(target Module, required []Module, g Graph) ([]Module, error) {
+	requirements := map[string][]Module{target.String(): required}
+	selected := map[string]*semver.Version{target.Path: target.Version}
+	queue := append([]Module{}, required...)
+	for len(queue) > 0 {
+		m := queue[0]
+		queue = queue[1:]
+		if _, ok := requirements[m.String()]; ok || m.Path == target.Path {
+			continue
+		}
+		if v, ok := selected[m.Path]; !ok || semver.Compare(m.Version, v) > 0 {
+			selected[m.Path] = m.Version
+		}
+		list, err := g.Required(m)
+		if err != nil {
+			return nil, err
+		}
+		requirements[m.String()] = list
+		queue = append(queue, list...)
+	}
+	// Older versions may require modules that the selected ones do not need anymore
+	list := []Module{target}
+	listed := map[string]bool{target.Path: true}
+	for i := 0; i < len(list); i++ {
+		for _, r := range requirements[list[i].String()] {
+			if !listed[r.Path] {
+				listed[r.Path] = true
+				list = append(list, Module{Path: r.Path, Version: selected[r.Path]})
+			}
+		}
+	}
+	sort.Sort(byPath(list[1:]))
+	return list, nil
+}
+
+// Upgrade returns the build list of target after upgrading the given modules. The upgrades are
+// added to the current build list as requirements of target, so no module is downgraded and
+// an upgrade to a version older than the selected one has no effect. Modules that were not
+// in the build list are added to it
+func Upgrade(target Module, g Graph, upgrades ...Module) ([]Module, error) {
+	list, err := BuildList(target, g)
+	if err != nil {
+		return nil, err
+	}
+	return buildList(target, append(list[1:], upgrades...), g)
+}
+
+// Downgrade returns the build list of target after downgrading the given modules, so that their
+// selected versions are not higher than the requested ones. The modules of the build list that
+// require a version too high, directly or indirectly, are downgraded to their highest version
+// that does not, and they are removed if there is none. No module is upgraded
+func Downgrade(target Module, g Graph, downgrades ...Module) ([]Module, error) {
+	list, err := BuildList(target, g)
+	if err != nil {
+		return nil, err
+	}
+	max := map[string]*semver.Version{}
+	for _, m := range list[1:] {
+		max[m.Path] = m.Version
+	}
+	for _, d := range downgrades {
+		if v, ok := max[d.Path]; d.Path != target.Path && (!ok || semver.Compare(d.Version, v) < 0) {
+			max[d.Path] = d.Version
+		}
+	}
+	// As in the go command, a module is excluded if it is higher than allowed or it requires an
+	// excluded module. Exclusions are propagated to the modules requiring it, which are recorded in
+	// rdeps, so modules of a cycle are excluded together even if they were added before
+	added := map[string]bool{}
+	excluded := map[string]bool{}
+	rdeps := map[string][]Module{}
+	var exclude func(m Module)
+	exclude = func(m Module) {
+		if excluded[m.String()] {
+			return
+		}
+		excluded[m.String()] = true
+		for _, p := range rdeps[m.String()] {
+			exclude(p)
+		}
+	}
+	var addErr error
+	var add func(m Module)
+	add = func(m Module) {
+		if added[m.String()] || addErr != nil {
+			return
+		}
+		added[m.String()] = true
+		if v, ok := max[m.Path]; ok && semver.Compare(m.Version, v) > 0 {
+			exclude(m)
+			return
+		}
+		required, err := g.Required(m)
+		if err != nil {
+			addErr = err
+			return
+		}
+		for _, r := range required {
+			add(r)
+			if excluded[r.String()] {
+				exclude(m)
+				return
+			}
+			rdeps[r.String()] = append(rdeps[r.String()], m)
+		}
+	}
+	downgraded := []Module{}
+	for _, m := range list[1:] {
+		add(m)
+		if excluded[m.String()] {
+			versions, err := g.Versions(m.Path)
+			if err != nil {
+				return nil, err
+			}
+			for i := len(versions) - 1; i >= 0 && excluded[m.String()]; i-- {
+				if semver.Compare(versions[i], m.Version) < 0 {
+					m = Module{Path: m.Path, Version: versions[i]}
+					add(m)
+				}
+			}
+		}
+		if addErr != nil {
+			return nil, addErr
+		}
+		// Modules without an old enough version are removed
+		if !excluded[m.String()] {
+			downgraded = append(downgraded, m)
+		}
+	}
+	return buildList(target, downgraded, g)
+}
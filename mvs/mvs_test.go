@@ -0,0 +1,177 @@
+package mvs
+
+import (
+	"strings"
+	"testing"
+)
+
+// testGraph is the example of the Minimal Version Selection design, including a cycle between f and g
+var testGraph = map[string][]string{
+	"a@1.0.0":      {"b@1.2.0", "c@1.2.0"},
+	"b@1.1.0":      {"d@1.1.0"},
+	"b@1.2.0":      {"d@1.3.0"},
+	"c@1.1.0":      {},
+	"c@1.2.0":      {"d@1.4.0"},
+	"c@1.3.0":      {"f@1.1.0"},
+	"d@1.1.0":      {"e@1.1.0"},
+	"d@1.2.0":      {"e@1.1.0"},
+	"d@1.3.0":      {"e@1.2.0"},
+	"d@1.4.0":      {"e@1.2.0"},
+	"e@1.1.0":      {},
+	"e@1.2.0":      {},
+	"e@1.3.0-rc.1": {},
+	"e@1.3.0":      {},
+	"f@1.1.0":      {"g@1.1.0"},
+	"g@1.1.0":      {"f@1.1.0"},
+	"h@1.0.0":      {"e@1.3.0-rc.1"},
+	"h@1.1.0":      {"i@1.0.0"},
+	"i@1.0.0":      {"h@1.0.0", "e@1.3.0"},
+	"j@1.0.0":      {"e@1.3.0-rc.1", "i@1.0.0"},
+}
+
+func newTestRegistry() *MemoryRegistry {
+	r := NewMemoryRegistry()
+	for m, required := range testGraph {
+		r.Add(MustParseModule(m), parseModules(required)...)
+	}
+	return r
+}
+
+func parseModules(list []string) []Module {
+	modules := []Module{}
+	for _, m := range list {
+		modules = append(modules, MustParseModule(m))
+	}
+	return modules
+}
+
+func formatModules(list []Module) string {
+	strs := []string{}
+	for _, m := range list {
+		strs = append(strs, m.String())
+	}
+	return strings.Join(strs, " ")
+}
+
+func TestParseModule(t *testing.T) {
+	for str, expected := range map[string]string{
+		"a@1.0.0":                  "a@1.0.0",
+		"example.com/mod@v1.2":     "example.com/mod@1.2.0",
+		"example.com/mod@1.0.0-rc": "example.com/mod@1.0.0-rc",
+	} {
+		if m, err := ParseModule(str); err != nil || m.String() != expected {
+			t.Errorf("Expected module %q to be %q but got %q (%v)", str, expected, m, err)
+		}
+	}
+	for _, str := range []string{"", "a", "@1.0.0", "a@", "a@foo"} {
+		if _, err := ParseModule(str); err == nil {
+			t.Errorf("Expected module %q to be invalid", str)
+		}
+	}
+}
+
+func TestBuildList(t *testing.T) {
+	r := newTestRegistry()
+	for target, expected := range map[string]string{
+		"a@1.0.0": "a@1.0.0 b@1.2.0 c@1.2.0 d@1.4.0 e@1.2.0",
+		"c@1.3.0": "c@1.3.0 f@1.1.0 g@1.1.0",
+		"f@1.1.0": "f@1.1.0 g@1.1.0",
+		"e@1.1.0": "e@1.1.0",
+		"h@1.0.0": "h@1.0.0 e@1.3.0-rc.1",
+		// The target is always selected, even if a dependency requires another version
+		"h@1.1.0": "h@1.1.0 e@1.3.0 i@1.0.0",
+		"j@1.0.0": "j@1.0.0 e@1.3.0 h@1.0.0 i@1.0.0",
+	} {
+		list, err := BuildList(MustParseModule(target), r)
+		if err != nil {
+			t.Errorf("Expected the build list of %q to be computed but got %v", target, err)
+			continue
+		}
+		if res := formatModules(list); res != expected {
+			t.Errorf("Expected the build list of %q to be %q but got %q", target, expected, res)
+		}
+	}
+	if _, err := BuildList(MustParseModule("a@2.0.0"), r); err == nil {
+		t.Errorf("Expected the build list of a missing module to fail")
+	}
+	r.Add(MustParseModule("x@1.0.0"), MustParseModule("b@9.0.0"))
+	if _, err := BuildList(MustParseModule("x@1.0.0"), r); err == nil {
+		t.Errorf("Expected the build list of a module with missing requirements to fail")
+	}
+}
+
+func TestBuildListDropsUnusedModules(t *testing.T) {
+	r := NewMemoryRegistry()
+	r.Add(MustParseModule("a@1.0.0"), parseModules([]string{"b@1.0.0", "c@1.0.0"})...)
+	r.Add(MustParseModule("b@1.0.0"), MustParseModule("d@1.0.0"))
+	r.Add(MustParseModule("c@1.0.0"), MustParseModule("b@1.1.0"))
+	r.Add(MustParseModule("b@1.1.0"))
+	r.Add(MustParseModule("d@1.0.0"))
+	list, err := BuildList(MustParseModule("a@1.0.0"), r)
+	if res := formatModules(list); err != nil || res != "a@1.0.0 b@1.1.0 c@1.0.0" {
+		t.Errorf("Expected modules only required by older versions to be dropped but got %q (%v)", res, err)
+	}
+}
+
+func TestUpgrade(t *testing.T) {
+	r := newTestRegistry()
+	for _, test := range []struct {
+		upgrades []string
+		expected string
+	}{
+		{nil, "a@1.0.0 b@1.2.0 c@1.2.0 d@1.4.0 e@1.2.0"},
+		{[]string{"c@1.3.0"}, "a@1.0.0 b@1.2.0 c@1.3.0 d@1.4.0 e@1.2.0 f@1.1.0 g@1.1.0"},
+		{[]string{"e@1.3.0", "d@1.3.0"}, "a@1.0.0 b@1.2.0 c@1.2.0 d@1.4.0 e@1.3.0"},
+		{[]string{"c@1.1.0"}, "a@1.0.0 b@1.2.0 c@1.2.0 d@1.4.0 e@1.2.0"},
+		{[]string{"f@1.1.0"}, "a@1.0.0 b@1.2.0 c@1.2.0 d@1.4.0 e@1.2.0 f@1.1.0 g@1.1.0"},
+		{[]string{"a@2.0.0"}, "a@1.0.0 b@1.2.0 c@1.2.0 d@1.4.0 e@1.2.0"},
+	} {
+		list, err := Upgrade(MustParseModule("a@1.0.0"), r, parseModules(test.upgrades)...)
+		if res := formatModules(list); err != nil || res != test.expected {
+			t.Errorf("Expected upgrading %v to result in %q but got %q (%v)", test.upgrades, test.expected, res, err)
+		}
+	}
+}
+
+func TestDowngrade(t *testing.T) {
+	r := newTestRegistry()
+	for _, test := range []struct {
+		downgrades []string
+		expected   string
+	}{
+		{nil, "a@1.0.0 b@1.2.0 c@1.2.0 d@1.4.0 e@1.2.0"},
+		{[]string{"d@1.2.0"}, "a@1.0.0 b@1.1.0 c@1.1.0 d@1.2.0 e@1.2.0"},
+		{[]string{"d@1.3.0"}, "a@1.0.0 b@1.2.0 c@1.1.0 d@1.3.0 e@1.2.0"},
+		{[]string{"c@1.1.0"}, "a@1.0.0 b@1.2.0 c@1.1.0 d@1.4.0 e@1.2.0"},
+		{[]string{"e@1.1.0"}, "a@1.0.0 b@1.1.0 c@1.1.0 d@1.2.0 e@1.1.0"},
+		// No version of d is old enough, so the modules requiring it are removed
+		{[]string{"d@1.0.0"}, "a@1.0.0 c@1.1.0 e@1.2.0"},
+		{[]string{"b@1.5.0"}, "a@1.0.0 b@1.2.0 c@1.2.0 d@1.4.0 e@1.2.0"},
+		{[]string{"a@0.1.0"}, "a@1.0.0 b@1.2.0 c@1.2.0 d@1.4.0 e@1.2.0"},
+	} {
+		list, err := Downgrade(MustParseModule("a@1.0.0"), r, parseModules(test.downgrades)...)
+		if res := formatModules(list); err != nil || res != test.expected {
+			t.Errorf("Expected downgrading %v to result in %q but got %q (%v)", test.downgrades, test.expected, res, err)
+		}
+	}
+	list, err := Downgrade(MustParseModule("c@1.3.0"), r, MustParseModule("g@1.0.0"))
+	if res := formatModules(list); err != nil || res != "c@1.3.0" {
+		t.Errorf("Expected downgrading a cycle to remove it but got %q (%v)", res, err)
+	}
+	// Modules of a cycle are removed together, whichever of them requires the version too high
+	for _, graph := range []map[string][]string{
+		{"t@1.0.0": {"a@1.0.0", "b@1.0.0"}, "a@1.0.0": {"b@1.0.0", "c@2.0.0"}, "b@1.0.0": {"a@1.0.0"}},
+		{"t@1.0.0": {"a@1.0.0", "b@1.0.0"}, "a@1.0.0": {"b@1.0.0"}, "b@1.0.0": {"a@1.0.0", "c@2.0.0"}},
+	} {
+		r := NewMemoryRegistry()
+		r.Add(MustParseModule("c@1.0.0"))
+		r.Add(MustParseModule("c@2.0.0"))
+		for m, required := range graph {
+			r.Add(MustParseModule(m), parseModules(required)...)
+		}
+		list, err := Downgrade(MustParseModule("t@1.0.0"), r, MustParseModule("c@1.0.0"))
+		if res := formatModules(list); err != nil || res != "t@1.0.0 c@1.0.0" {
+			t.Errorf("Expected downgrading c in %v to remove the cycle but got %q (%v)", graph, res, err)
+		}
+	}
+}
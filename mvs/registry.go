@@ -0,0 +1,150 @@
+package mvs
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/juamedgod/semver"
+)
+
+// MemoryRegistry is a Graph whose modules are kept in memory
+type MemoryRegistry struct {
+	requirements map[string][]Module
+	versions     map[string][]*semver.Version
+}
+
+// NewMemoryRegistry returns an empty MemoryRegistry
+func NewMemoryRegistry() *MemoryRegistry {
+	return &MemoryRegistry{requirements: map[string][]Module{}, versions: map[string][]*semver.Version{}}
+}
+
+// Add adds the module version m to the registry, replacing its requirements if it was already added
+func (r *MemoryRegistry) Add(m Module, requirements ...Module) {
+	if _, ok := r.requirements[m.String()]; !ok {
+		r.versions[m.Path] = append(r.versions[m.Path], m.Version)
+		semver.Sort(r.versions[m.Path])
+	}
+	r.requirements[m.String()] = append([]Module{}, requirements...)
+}
+
+// Required returns the requirements of m. It fails if m was not added to the registry
+func (r *MemoryRegistry) Required(m Module) ([]Module, error) {
+	required, ok := r.requirements[m.String()]
+	if !ok {
+		return nil, fmt.Errorf("module %s not found", m)
+	}
+	return append([]Module{}, required...), nil
+}
+
+// Versions returns the versions of the module added to the registry, in ascending order
+func (r *MemoryRegistry) Versions(path string) ([]*semver.Version, error) {
+	return append([]*semver.Version{}, r.versions[path]...), nil
+}
+
+// modExt is the extension of the files of a DirRegistry
+const modExt = ".mod"
+
+// DirRegistry is a Graph whose modules are stored in a directory, with a subdirectory per module
+// path and a file per version, named after the version with the ".mod" extension: the requirements
+// of "example.com/a@1.2.0" are in "example.com/a/1.2.0.mod". Each line of a file is a requirement,
+// made of a module path and a version separated by spaces, such as "example.com/b 1.0.0". Empty
+// lines and lines starting with "#" are ignored
+type DirRegistry struct {
+	root string
+}
+
+// NewDirRegistry returns a DirRegistry stored in the root directory
+func NewDirRegistry(root string) *DirRegistry {
+	return &DirRegistry{root: root}
+}
+
+// dir returns the directory of the module with the given path
+func (r *DirRegistry) dir(path string) (string, error) {
+	for _, elem := range strings.Split(path, "/") {
+		if elem == "" || elem == "." || elem == ".." {
+			return "", fmt.Errorf("invalid module path %q", path)
+		}
+	}
+	return filepath.Join(r.root, filepath.FromSlash(path)), nil
+}
+
+// Add stores the module version m in the registry, replacing its requirements if it was already stored
+func (r *DirRegistry) Add(m Module, requirements ...Module) error {
+	dir, err := r.dir(m.Path)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	for _, req := range requirements {
+		fmt.Fprintf(&buf, "%s %s\n", req.Path, req.Version)
+	}
+	return ioutil.WriteFile(filepath.Join(dir, m.Version.String()+modExt), buf.Bytes(), 0644)
+}
+
+// Required reads the requirements of m. It fails if m is not stored in the registry
+func (r *DirRegistry) Required(m Module) ([]Module, error) {
+	dir, err := r.dir(m.Path)
+	if err != nil {
+		return nil, err
+	}
+	file := filepath.Join(dir, m.Version.String()+modExt)
+	data, err := ioutil.ReadFile(file)
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("module %s not found", m)
+	} else if err != nil {
+		return nil, err
+	}
+	required := []Module{}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		fields := strings.Fields(text)
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("%s:%d: malformed requirement %q: expected path and version", file, line, text)
+		}
+		v, err := semver.ParseVersion(fields[1])
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: malformed requirement %q: %v", file, line, text, err)
+		}
+		required = append(required, Module{Path: fields[0], Version: v})
+	}
+	return required, scanner.Err()
+}
+
+// Versions returns the versions of the module stored in the registry, in ascending order
+func (r *DirRegistry) Versions(path string) ([]*semver.Version, error) {
+	dir, err := r.dir(path)
+	if err != nil {
+		return nil, err
+	}
+	files, err := ioutil.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return []*semver.Version{}, nil
+	} else if err != nil {
+		return nil, err
+	}
+	versions := []*semver.Version{}
+	for _, f := range files {
+		if f.IsDir() || !strings.HasSuffix(f.Name(), modExt) {
+			continue
+		}
+		v, err := semver.ParseVersion(strings.TrimSuffix(f.Name(), modExt))
+		if err != nil {
+			return nil, fmt.Errorf("malformed version file %q: %v", filepath.Join(dir, f.Name()), err)
+		}
+		versions = append(versions, v)
+	}
+	semver.Sort(versions)
+	return versions, nil
+}
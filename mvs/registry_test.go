@@ -0,0 +1,101 @@
+package mvs
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestMemoryRegistry(t *testing.T) {
+	r := newTestRegistry()
+	r.Add(MustParseModule("b@1.1.0"), MustParseModule("d@1.2.0"))
+	if required, err := r.Required(MustParseModule("b@1.1.0")); err != nil || formatModules(required) != "d@1.2.0" {
+		t.Errorf("Expected adding a module again to replace its requirements but got %v (%v)", required, err)
+	}
+	versions, err := r.Versions("d")
+	if err != nil || len(versions) != 4 || versions[0].String() != "1.1.0" || versions[3].String() != "1.4.0" {
+		t.Errorf("Expected the versions of d to be sorted but got %v (%v)", versions, err)
+	}
+	if versions, err := r.Versions("missing"); err != nil || len(versions) != 0 {
+		t.Errorf("Expected a missing module to have no versions but got %v (%v)", versions, err)
+	}
+	if _, err := r.Required(MustParseModule("d@9.0.0")); err == nil {
+		t.Errorf("Expected the requirements of a missing module to fail")
+	}
+}
+
+func TestDirRegistry(t *testing.T) {
+	root, err := ioutil.TempDir("", "mvs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+	r := NewDirRegistry(root)
+	for m, required := range testGraph {
+		if err := r.Add(MustParseModule(m), parseModules(required)...); err != nil {
+			t.Fatal(err)
+		}
+	}
+	list, err := BuildList(MustParseModule("a@1.0.0"), r)
+	if res := formatModules(list); err != nil || res != "a@1.0.0 b@1.2.0 c@1.2.0 d@1.4.0 e@1.2.0" {
+		t.Errorf("Expected the build list to be read from the directory but got %q (%v)", res, err)
+	}
+	list, err = Downgrade(MustParseModule("a@1.0.0"), r, MustParseModule("d@1.2.0"))
+	if res := formatModules(list); err != nil || res != "a@1.0.0 b@1.1.0 c@1.1.0 d@1.2.0 e@1.2.0" {
+		t.Errorf("Expected the versions to be read from the directory but got %q (%v)", res, err)
+	}
+	if versions, err := r.Versions("missing"); err != nil || len(versions) != 0 {
+		t.Errorf("Expected a missing module to have no versions but got %v (%v)", versions, err)
+	}
+	if _, err := r.Required(MustParseModule("d@9.0.0")); err == nil {
+		t.Errorf("Expected the requirements of a missing module to fail")
+	}
+}
+
+func TestDirRegistryFiles(t *testing.T) {
+	root, err := ioutil.TempDir("", "mvs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+	dir := filepath.Join(root, "example.com", "a")
+	if err := os.MkdirAll(filepath.Join(dir, "v2"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	for name, content := range map[string]string{
+		"1.0.0.mod":  "# Comments and empty lines are ignored\n\n  example.com/b  1.2.0 \nexample.com/c v1.0\n",
+		"1.1.0.mod":  "example.com/b\n",
+		"1.2.0.mod":  "example.com/b 1.x\n",
+		"README.txt": "Ignored",
+	} {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	r := NewDirRegistry(root)
+	required, err := r.Required(MustParseModule("example.com/a@1.0.0"))
+	if res := formatModules(required); err != nil || res != "example.com/b@1.2.0 example.com/c@1.0.0" {
+		t.Errorf("Expected the requirements to be parsed but got %q (%v)", res, err)
+	}
+	for _, m := range []string{"example.com/a@1.1.0", "example.com/a@1.2.0"} {
+		if _, err := r.Required(MustParseModule(m)); err == nil {
+			t.Errorf("Expected the malformed requirements of %q to fail", m)
+		}
+	}
+	versions, err := r.Versions("example.com/a")
+	if err != nil || len(versions) != 3 || versions[0].String() != "1.0.0" || versions[2].String() != "1.2.0" {
+		t.Errorf("Expected the versions to be read from the file names but got %v (%v)", versions, err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, "latest.mod"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Versions("example.com/a"); err == nil {
+		t.Errorf("Expected a malformed version file name to fail")
+	}
+	for _, path := range []string{"../a", "example.com//a", "/a", "a/."} {
+		if err := r.Add(Module{Path: path, Version: MustParseModule("a@1.0.0").Version}); err == nil {
+			t.Errorf("Expected the invalid module path %q to be rejected", path)
+		}
+	}
+}
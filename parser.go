@@ -0,0 +1,213 @@
+package semver
+
+import "regexp"
+
+var globVersionPrefixRe = regexp.MustCompile(`^` + rangedVersionRe.String())
+
+// parser implements a recursive descent parser over the tokens of an expression.
+// AND binds tighter than OR, and a missing operator between two terms means AND:
+//
+//	expression := and ( "||" and )*
+//	and        := unary ( "&&"? unary )*
+//	unary      := "!" unary | "(" expression ")" | "!=" version | range
+//	range      := operator version | version ( "-" version )?
+type parser struct {
+	input  string
+	tokens []token
+	pos    int
+	// end is the byte offset right after the last consumed token
+	end int
+	parseConfig
+}
+
+type parseConfig struct {
+	includePrerelease bool
+}
+
+// ParseOption configures how expressions and ranges are parsed
+type ParseOption func(c *parseConfig)
+
+// IncludePrerelease makes pre-release versions satisfy the parsed ranges whenever they are
+// within their limits. By default, a pre-release version is only accepted if a range limit is
+// a pre-release of the same major, minor and patch (npm semantics), so ">=1.2.3-beta.2" accepts
+// "1.2.3-beta.3" but not "3.0.0-alpha". As in npm, caret, tilde, hyphen and x-range limits are then
+// the lowest pre-releases they bound, so "^1.2.3" is ">=1.2.3 <2.0.0-0"
+var IncludePrerelease ParseOption = func(c *parseConfig) {
+	c.includePrerelease = true
+}
+
+func newParser(str string, opts ...ParseOption) (*parser, error) {
+	tokens, err := lex(str)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{input: str, tokens: tokens}
+	for _, opt := range opts {
+		opt(&p.parseConfig)
+	}
+	return p, nil
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.typ != tokenEOF {
+		p.pos++
+		p.end = t.pos + len(t.text)
+	}
+	return t
+}
+
+func (p *parser) errorAt(t token, expected string) *ParseError {
+	return &ParseError{Input: p.input, Offset: t.pos, Token: t.text, Expected: expected}
+}
+
+// expectEOF returns an error if there are tokens left to parse
+func (p *parser) expectEOF(expected string) error {
+	if t := p.peek(); t.typ != tokenEOF {
+		return p.errorAt(t, expected)
+	}
+	return nil
+}
+
+// parse parses the whole input as an expression. An empty input matches any version
+func (p *parser) parse() (Node, error) {
+	if p.peek().typ == tokenEOF {
+		return &AndNode{Location: Span{Start: 0, End: len(p.input)}}, nil
+	}
+	n, err := p.parseExpression()
+	if err != nil {
+		return nil, err
+	}
+	return n, p.expectEOF("end of input")
+}
+
+func (p *parser) parseExpression() (Node, error) {
+	start := p.peek().pos
+	n, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	operands := []Node{n}
+	for p.peek().typ == tokenOr {
+		p.next()
+		n, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		operands = append(operands, n)
+	}
+	if len(operands) == 1 {
+		return n, nil
+	}
+	return &OrNode{Operands: operands, Location: Span{Start: start, End: p.end}}, nil
+}
+
+func (p *parser) parseAnd() (Node, error) {
+	start := p.peek().pos
+	n, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	operands := []Node{n}
+loop:
+	for {
+		switch p.peek().typ {
+		case tokenAnd:
+			p.next()
+		case tokenVersion, tokenOperator, tokenNot, tokenLParen:
+		default:
+			break loop
+		}
+		n, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		operands = append(operands, n)
+	}
+	if len(operands) == 1 {
+		return n, nil
+	}
+	return &AndNode{Operands: operands, Location: Span{Start: start, End: p.end}}, nil
+}
+
+func (p *parser) parseUnary() (Node, error) {
+	start := p.peek().pos
+	switch t := p.peek(); {
+	case t.typ == tokenNot:
+		p.next()
+		n, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &NotNode{Operand: n, Location: Span{Start: start, End: p.end}}, nil
+	case t.typ == tokenLParen:
+		p.next()
+		n, err := p.parseExpression()
+		if err != nil {
+			return nil, err
+		}
+		if t := p.peek(); t.typ != tokenRParen {
+			return nil, p.errorAt(t, `"&&", "||" or ")"`)
+		}
+		p.next()
+		return n, nil
+	case t.typ == tokenOperator && t.text == "!=":
+		p.next()
+		v, err := p.parseVersion()
+		if err != nil {
+			return nil, err
+		}
+		r, err := newRange("=", v, nil, p.parseConfig)
+		if err != nil {
+			return nil, err
+		}
+		location := Span{Start: start, End: p.end}
+		return &NotNode{Operand: &RangeNode{Range: r, Operator: "=", Location: location}, Location: location}, nil
+	case t.typ == tokenOperator || t.typ == tokenVersion:
+		return p.parseRange()
+	default:
+		return nil, p.errorAt(t, expectedTerm)
+	}
+}
+
+func (p *parser) parseRange() (*RangeNode, error) {
+	start := p.peek().pos
+	operator := ""
+	if t := p.peek(); t.typ == tokenOperator && t.text != "!=" {
+		operator = p.next().text
+	} else if t.typ != tokenVersion {
+		return nil, p.errorAt(t, "version or range operator")
+	}
+	v1, err := p.parseVersion()
+	if err != nil {
+		return nil, err
+	}
+	var v2 *GlobVersion
+	if operator == "" && p.peek().typ == tokenHyphen {
+		operator = p.next().text
+		if v2, err = p.parseVersion(); err != nil {
+			return nil, err
+		}
+	}
+	r, err := newRange(operator, v1, v2, p.parseConfig)
+	if err != nil {
+		return nil, err
+	}
+	return &RangeNode{Range: r, Operator: operator, Location: Span{Start: start, End: p.end}}, nil
+}
+
+func (p *parser) parseVersion() (*GlobVersion, error) {
+	t := p.peek()
+	if t.typ != tokenVersion {
+		return nil, p.errorAt(t, "version")
+	}
+	if n := len(globVersionPrefixRe.FindString(t.text)); n != len(t.text) {
+		return nil, &ParseError{Input: p.input, Offset: t.pos + n, Token: t.text[n:], Expected: "version"}
+	}
+	p.next()
+	return ParseGlobVersion(t.text)
+}
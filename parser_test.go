@@ -0,0 +1,83 @@
+package semver
+
+import "testing"
+
+var parseErrorTestBattery = map[string]ParseError{
+	"foo":                {Offset: 0, Token: "f", Expected: expectedTerm},
+	">=1.2.3 <2.0 foo":   {Offset: 13, Token: "f", Expected: expectedTerm},
+	"1.2.3 | 2.0.0":      {Offset: 6, Token: "|", Expected: `"||"`},
+	"1.2.3 ||":           {Offset: 8, Token: "", Expected: expectedTerm},
+	"|| 1.2.3":           {Offset: 0, Token: "||", Expected: expectedTerm},
+	">=":                 {Offset: 2, Token: "", Expected: "version"},
+	">= || 1.0":          {Offset: 3, Token: "||", Expected: "version"},
+	"1.2.3 -":            {Offset: 7, Token: "", Expected: "version"},
+	"1.2.3 - - 2.0.0":    {Offset: 8, Token: "-", Expected: "version"},
+	">1.2.3 - 2.0.0":     {Offset: 7, Token: "-", Expected: "end of input"},
+	"1.2.3.4":            {Offset: 5, Token: ".4", Expected: "version"},
+	"^1.2 <2.x.y":        {Offset: 9, Token: ".y", Expected: "version"},
+	"1.2.3 || 2.0.0 €":   {Offset: 15, Token: "€", Expected: expectedTerm},
+	"vv":                 {Offset: 0, Token: "vv", Expected: "version"},
+	"(1.2.3 || 2.0.0":    {Offset: 15, Token: "", Expected: `"&&", "||" or ")"`},
+	"1.2.3)":             {Offset: 5, Token: ")", Expected: "end of input"},
+	"()":                 {Offset: 1, Token: ")", Expected: expectedTerm},
+	"1.2.3 & 2.0.0":      {Offset: 6, Token: "&", Expected: `"&&"`},
+	"1.2.3 && || 2.0.0":  {Offset: 9, Token: "||", Expected: expectedTerm},
+	"!":                  {Offset: 1, Token: "", Expected: expectedTerm},
+	"!= <1.0":            {Offset: 3, Token: "<", Expected: "version"},
+	">=1.2.3 <1.2.3 <<1": {Offset: 16, Token: "<", Expected: "version"},
+}
+
+func TestParseErrors(t *testing.T) {
+	for str, expected := range parseErrorTestBattery {
+		_, err := ParseExpr(str)
+		pErr, ok := err.(*ParseError)
+		if !ok {
+			t.Errorf("Expected ParseExpr(%q) to fail with a *ParseError but got %v", str, err)
+			continue
+		}
+		if pErr.Input != str || pErr.Offset != expected.Offset || pErr.Token != expected.Token || pErr.Expected != expected.Expected {
+			t.Errorf("Expected ParseExpr(%q) to fail at offset %d with token %q (expected %s) but got %q",
+				str, expected.Offset, expected.Token, expected.Expected, pErr)
+		}
+	}
+}
+
+func TestParseRangeErrors(t *testing.T) {
+	for str, offset := range map[string]int{
+		"asdf":          0,
+		">1.3.5 <2.0.0": 7,
+		"1.2 || 1.3":    4,
+		"~":             1,
+		"!=1.2.3":       0,
+		"(1.2.3)":       0,
+	} {
+		_, err := ParseRange(str)
+		if pErr, ok := err.(*ParseError); !ok || pErr.Offset != offset {
+			t.Errorf("Expected ParseRange(%q) to fail at offset %d but got %v", str, offset, err)
+		}
+	}
+}
+
+func TestParseWhitespace(t *testing.T) {
+	for str, data := range map[string]map[string]bool{
+		"  >= 1.2.3   <  2  ": {"1.2.3": true, "2.0.0": false, "1.2.2": false},
+		"1.0.0-1.2.0":         {"1.0.0-1.2.0": true, "1.0.0": false, "1.1.0": false},
+		"1.0.0 - 1.2.0||>3":   {"1.1.0": true, "4.1.0": true, "2.0.0": false},
+		"\t1.x\n||\t3.x":      {"1.4.0": true, "3.1.0": true, "2.0.0": false},
+		"=v1.2.3":             {"1.2.3": true, "1.2.4": false},
+		"~>1.2":               {"1.2.9": true, "1.3.0": false},
+		"":                    {"1.2.3": true},
+		"   ":                 {"0.0.1": true},
+	} {
+		e, err := ParseExpr(str)
+		if err != nil {
+			t.Errorf("Expected %q to be parseable but got %v", str, err)
+			continue
+		}
+		for vStr, result := range data {
+			if e.Matches(MustParseVersion(vStr)) != result {
+				t.Errorf("Expected %q of %v to evaluate to %v", str, vStr, result)
+			}
+		}
+	}
+}
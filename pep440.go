@@ -0,0 +1,476 @@
+package semver
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+var (
+	pep440VersionRe = regexp.MustCompile(`(?i)^v?(?:([0-9]+)!)?([0-9]+(?:\.[0-9]+)*)` +
+		`(?:[-_.]?(alpha|a|beta|b|preview|pre|c|rc)[-_.]?([0-9]+)?)?` +
+		`(?:-([0-9]+)|[-_.]?(post|rev|r)[-_.]?([0-9]+)?)?` +
+		`(?:[-_.]?(dev)[-_.]?([0-9]+)?)?` +
+		`(?:\+([a-z0-9]+(?:[-_.][a-z0-9]+)*))?$`)
+	pep440LocalSeparatorRe = regexp.MustCompile(`[-_.]`)
+	pep440SpecifierRe      = regexp.MustCompile(`^(===|~=|==|!=|<=|>=|<|>)\s*`)
+)
+
+// pep440PreReleases maps the spellings of pre-release labels onto their normalized form
+var pep440PreReleases = map[string]string{
+	"a": "a", "alpha": "a", "b": "b", "beta": "b", "c": "rc", "rc": "rc", "pre": "rc", "preview": "rc",
+}
+
+// PEP440Version describes a version of a Python package, following PEP 440: an optional epoch,
+// a release made of any number of components and optional pre-release, post-release, development
+// release and local labels, such as "1!2.0rc1.post2.dev3+ubuntu.1". Numbers can have any length,
+// so they are kept as digits without leading zeros
+type PEP440Version struct {
+	str     string
+	epoch   string
+	release []string
+	// pre is "a", "b" or "rc", and it is empty if the version is not a pre-release
+	pre       string
+	preNumber string
+	// post and dev are empty if the version has no such label
+	post  string
+	dev   string
+	local []string
+}
+
+// MustParsePEP440Version parses a PEP 440 version
+// It panics if it cannot be parsed
+func MustParsePEP440Version(str string) *PEP440Version {
+	if v, err := ParsePEP440Version(str); err != nil {
+		panic(err)
+	} else {
+		return v
+	}
+}
+
+// ParsePEP440Version parses a PEP 440 version. Alternative spellings are accepted and normalized
+// as PEP 440 describes: letters are case insensitive, "v1.0" is "1.0", "1.0-alpha.1" is "1.0a1",
+// "1.0c1" and "1.0pre1" are "1.0rc1", "1.0-1" and "1.0rev1" are "1.0.post1", "1.0dev" is
+// "1.0.dev0" and "1.0+ubuntu-1" is "1.0+ubuntu.1"
+func ParsePEP440Version(str string) (*PEP440Version, error) {
+	m := pep440VersionRe.FindStringSubmatch(strings.TrimSpace(str))
+	if m == nil {
+		return nil, fmt.Errorf("malformed PEP 440 version string %q", str)
+	}
+	v := &PEP440Version{str: str, epoch: trimDigits(m[1])}
+	for _, n := range strings.Split(m[2], ".") {
+		v.release = append(v.release, trimDigits(n))
+	}
+	if m[3] != "" {
+		v.pre, v.preNumber = pep440PreReleases[strings.ToLower(m[3])], trimDigits(m[4])
+	}
+	switch {
+	case m[5] != "":
+		v.post = trimDigits(m[5])
+	case m[6] != "":
+		v.post = trimDigits(m[7])
+	}
+	if m[8] != "" {
+		v.dev = trimDigits(m[9])
+	}
+	if m[10] != "" {
+		for _, s := range pep440LocalSeparatorRe.Split(strings.ToLower(m[10]), -1) {
+			if isDigits(s) {
+				s = trimDigits(s)
+			}
+			v.local = append(v.local, s)
+		}
+	}
+	return v, nil
+}
+
+func isDigits(str string) bool {
+	for i := 0; i < len(str); i++ {
+		if !isDigit(str[i]) {
+			return false
+		}
+	}
+	return str != ""
+}
+
+// pep440VersionOf returns the PEP 440 version equivalent to the semver v, ignoring its build
+// metadata, or nil if its pre-release is not a PEP 440 label: "1.0.0-rc.1" is "1.0.0rc1" but
+// "1.0.0-1", which would be a post-release, has no equivalent
+func pep440VersionOf(v *Version) *PEP440Version {
+	if isDigits(v.PreRelease) {
+		return nil
+	}
+	str := fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+	if v.PreRelease != "" {
+		str += "-" + v.PreRelease
+	}
+	if pv, err := ParsePEP440Version(str); err == nil {
+		return pv
+	}
+	return nil
+}
+
+func (v *PEP440Version) String() string {
+	return v.str
+}
+
+// Canonical returns the normalized form of the version: "v1.0-ALPHA.1" is "1.0a1" and
+// "0!1.0-r.2" is "1.0.post2"
+func (v *PEP440Version) Canonical() string {
+	str := ""
+	if v.epoch != "0" {
+		str = v.epoch + "!"
+	}
+	str += strings.Join(v.release, ".")
+	if v.pre != "" {
+		str += v.pre + v.preNumber
+	}
+	if v.post != "" {
+		str += ".post" + v.post
+	}
+	if v.dev != "" {
+		str += ".dev" + v.dev
+	}
+	if len(v.local) > 0 {
+		str += "+" + strings.Join(v.local, ".")
+	}
+	return str
+}
+
+// IsPreRelease returns true if the version has a pre-release or a development release label
+func (v *PEP440Version) IsPreRelease() bool {
+	return v.pre != "" || v.dev != ""
+}
+
+// IsPostRelease returns true if the version has a post-release label
+func (v *PEP440Version) IsPostRelease() bool {
+	return v.post != ""
+}
+
+// IsDevRelease returns true if the version has a development release label
+func (v *PEP440Version) IsDevRelease() bool {
+	return v.dev != ""
+}
+
+// public returns the version without its local label
+func (v *PEP440Version) public() *PEP440Version {
+	public := *v
+	public.local = nil
+	return &public
+}
+
+// base returns the version made of the epoch and the release
+func (v *PEP440Version) base() *PEP440Version {
+	return &PEP440Version{epoch: v.epoch, release: v.release}
+}
+
+// releaseAt returns the component i of the release, which is zero if it is missing
+func (v *PEP440Version) releaseAt(i int) string {
+	if i < len(v.release) {
+		return v.release[i]
+	}
+	return "0"
+}
+
+// hasPrefix checks if v has the epoch of prefix and if its release starts with the components
+// of the release of prefix, missing components being zeros
+func (v *PEP440Version) hasPrefix(prefix *PEP440Version) bool {
+	if v.epoch != prefix.epoch {
+		return false
+	}
+	for i, n := range prefix.release {
+		if v.releaseAt(i) != n {
+			return false
+		}
+	}
+	return true
+}
+
+// preReleaseRank places development releases of a release before its pre-releases, and
+// releases without a pre-release label after them
+func (v *PEP440Version) preReleaseRank() int {
+	switch {
+	case v.pre != "":
+		return 0
+	case v.post == "" && v.dev != "":
+		return -1
+	default:
+		return 1
+	}
+}
+
+// comparePEP440Numbers compares two optional numbers. missing is the result of comparing a missing
+// number with a present one
+func comparePEP440Numbers(n1, n2 string, missing int) int {
+	switch {
+	case n1 == "" && n2 == "":
+		return 0
+	case n1 == "":
+		return missing
+	case n2 == "":
+		return -missing
+	default:
+		return compareDigits(n1, n2)
+	}
+}
+
+// comparePEP440Locals compares two local labels segment by segment. Numeric segments are higher
+// than alphanumeric ones, and a label is lower than the labels it is a prefix of
+func comparePEP440Locals(l1, l2 []string) int {
+	for i := 0; i < len(l1) && i < len(l2); i++ {
+		res := 0
+		switch n1, n2 := isDigits(l1[i]), isDigits(l2[i]); {
+		case n1 && n2:
+			res = compareDigits(l1[i], l2[i])
+		case n1:
+			res = 1
+		case n2:
+			res = -1
+		default:
+			res = strings.Compare(l1[i], l2[i])
+		}
+		if res != 0 {
+			return res
+		}
+	}
+	return compareInt(len(l1), len(l2))
+}
+
+// Compare returns -1, 0 or 1 if v is respectively lower, equal or higher than v2, following the
+// ordering of PEP 440: epochs first, then releases, padded with zeros, and then labels, so that
+// "1.0.dev0" < "1.0a1.dev0" < "1.0a1" < "1.0rc1" < "1.0" < "1.0+local" < "1.0.post1.dev0" < "1.0.post1"
+func (v *PEP440Version) Compare(v2 *PEP440Version) int {
+	if res := compareDigits(v.epoch, v2.epoch); res != 0 {
+		return res
+	}
+	for i := 0; i < len(v.release) || i < len(v2.release); i++ {
+		if res := compareDigits(v.releaseAt(i), v2.releaseAt(i)); res != 0 {
+			return res
+		}
+	}
+	if res := compareInt(v.preReleaseRank(), v2.preReleaseRank()); res != 0 {
+		return res
+	}
+	if res := strings.Compare(v.pre, v2.pre); res != 0 {
+		return res
+	}
+	if res := comparePEP440Numbers(v.preNumber, v2.preNumber, -1); res != 0 {
+		return res
+	}
+	if res := comparePEP440Numbers(v.post, v2.post, -1); res != 0 {
+		return res
+	}
+	if res := comparePEP440Numbers(v.dev, v2.dev, 1); res != 0 {
+		return res
+	}
+	return comparePEP440Locals(v.local, v2.local)
+}
+
+// PEP440Versions defines a list of PEP 440 versions sortable in ascending order. It implements sort.Interface
+type PEP440Versions []*PEP440Version
+
+func (vs PEP440Versions) Len() int           { return len(vs) }
+func (vs PEP440Versions) Swap(i, j int)      { vs[i], vs[j] = vs[j], vs[i] }
+func (vs PEP440Versions) Less(i, j int) bool { return vs[i].Compare(vs[j]) < 0 }
+
+// SortPEP440Versions sorts the PEP 440 versions in ascending order. Equal versions, such as "1.0"
+// and "1.0.0", keep their relative order
+func SortPEP440Versions(versions []*PEP440Version) {
+	sort.Stable(PEP440Versions(versions))
+}
+
+// pep440Specifier is a single clause of a specifier set, such as "~=1.4.2" or "==1.4.*"
+type pep440Specifier struct {
+	operator string
+	// version is the version as written for "===", which compares strings
+	version string
+	v       *PEP440Version
+	// prefix is set for "==" and "!=" followed by a version ending in ".*"
+	prefix bool
+}
+
+// allowsPreReleases returns true if the specifier explicitly mentions a pre-release
+func (s pep440Specifier) allowsPreReleases() bool {
+	switch s.operator {
+	case "===":
+		v, err := ParsePEP440Version(s.version)
+		return err == nil && v.IsPreRelease()
+	case "!=":
+		return false
+	default:
+		return s.v.IsPreRelease()
+	}
+}
+
+func (s pep440Specifier) equals(v *PEP440Version) bool {
+	switch {
+	case s.prefix:
+		return v.hasPrefix(s.v)
+	case len(s.v.local) == 0:
+		return v.public().Compare(s.v) == 0
+	default:
+		return v.Compare(s.v) == 0
+	}
+}
+
+func (s pep440Specifier) contains(v *PEP440Version) bool {
+	if s.operator == "===" {
+		return strings.EqualFold(strings.TrimSpace(v.str), s.version)
+	}
+	res := v.public().Compare(s.v)
+	switch s.operator {
+	case "==":
+		return s.equals(v)
+	case "!=":
+		return !s.equals(v)
+	case "~=":
+		prefix := &PEP440Version{epoch: s.v.epoch, release: s.v.release[:len(s.v.release)-1]}
+		return res >= 0 && v.hasPrefix(prefix)
+	case ">=":
+		return res >= 0
+	case "<=":
+		return res <= 0
+	case "<":
+		// <1.0 does not contain 1.0rc1, unless the specifier is itself a pre-release
+		return res < 0 && (s.v.IsPreRelease() || !v.IsPreRelease() || v.base().Compare(s.v.base()) != 0)
+	default:
+		// >1.0 does not contain 1.0.post1, unless the specifier is itself a post-release
+		return res > 0 && (s.v.IsPostRelease() || !v.IsPostRelease() || v.base().Compare(s.v.base()) != 0)
+	}
+}
+
+// PEP440SpecifierSet describes a set of version specifiers of a Python package, such as ">=1.0,<2.0"
+// or "~=1.4.2,!=1.4.5". It implements the Expression interface by converting the versions into PEP 440
+// versions, but the set operations, which follow the SemVer ordering, return an error for it
+type PEP440SpecifierSet struct {
+	str               string
+	specifiers        []pep440Specifier
+	includePrerelease bool
+}
+
+// MustParsePEP440SpecifierSet parses a set of PEP 440 version specifiers
+// It panics if it cannot be parsed
+func MustParsePEP440SpecifierSet(str string, opts ...ParseOption) *PEP440SpecifierSet {
+	if s, err := ParsePEP440SpecifierSet(str, opts...); err != nil {
+		panic(err)
+	} else {
+		return s
+	}
+}
+
+// ParsePEP440SpecifierSet parses a list of comma separated PEP 440 version specifiers, made of an
+// operator and a PEP 440 version:
+//
+//   - "~=1.4.2" is a compatible release, ">=1.4.2,==1.4.*". The version needs at least two components
+//   - "==1.4.2" and "!=1.4.2" compare versions, ignoring the local label unless the specifier has one.
+//     Followed by ".*", as in "==1.4.*", they match the versions starting with the given release
+//   - "<", ">", "<=" and ">=" are ordered comparisons. "<1.0" excludes the pre-releases of 1.0 and
+//     ">1.0" excludes its post-releases, unless the specifier is itself a pre-release or a post-release
+//   - "===" compares versions as case insensitive strings
+//
+// As in PEP 440, pre-releases are excluded unless a specifier other than "!=" mentions one, as in ">=1.0rc1", or
+// IncludePrerelease is given. An empty set contains any release. Syntax errors are reported as a *ParseError
+func ParsePEP440SpecifierSet(str string, opts ...ParseOption) (*PEP440SpecifierSet, error) {
+	var c parseConfig
+	for _, opt := range opts {
+		opt(&c)
+	}
+	s := &PEP440SpecifierSet{str: str, includePrerelease: c.includePrerelease}
+	if strings.TrimSpace(str) == "" {
+		return s, nil
+	}
+	offset := 0
+	for _, item := range strings.Split(str, ",") {
+		start := offset + len(item) - len(strings.TrimLeftFunc(item, unicode.IsSpace))
+		offset += len(item) + 1
+		m := pep440SpecifierRe.FindStringSubmatch(strings.TrimSpace(item))
+		if m == nil {
+			return nil, pep440ErrorAt(str, start, "operator")
+		}
+		versionStart := start + len(m[0])
+		spec, expected := parsePEP440Specifier(m[1], strings.TrimSpace(str[versionStart:offset-1]))
+		if expected != "" {
+			return nil, pep440ErrorAt(str, versionStart, expected)
+		}
+		s.specifiers = append(s.specifiers, spec)
+	}
+	return s, nil
+}
+
+// parsePEP440Specifier returns the specifier made of the operator and the version, or a description
+// of what was expected if the version is not valid for the operator
+func parsePEP440Specifier(operator, version string) (pep440Specifier, string) {
+	s := pep440Specifier{operator: operator, version: version}
+	if operator == "===" {
+		if version == "" {
+			return s, "version"
+		}
+		return s, ""
+	}
+	if operator == "==" || operator == "!=" {
+		s.prefix = strings.HasSuffix(version, ".*")
+		version = strings.TrimSuffix(version, ".*")
+	}
+	v, err := ParsePEP440Version(version)
+	switch {
+	case err != nil:
+		return s, "PEP 440 version"
+	case s.prefix && (v.pre != "" || v.post != "" || v.dev != "" || len(v.local) > 0):
+		return s, "release before .*"
+	case len(v.local) > 0 && operator != "==" && operator != "!=":
+		return s, "version without local label"
+	case operator == "~=" && len(v.release) < 2:
+		return s, "version with at least two components"
+	}
+	s.v = v
+	return s, ""
+}
+
+func pep440ErrorAt(str string, offset int, expected string) *ParseError {
+	token := ""
+	if offset < len(str) {
+		token = str[offset : offset+1]
+	}
+	return &ParseError{Input: str, Offset: offset, Token: token, Expected: expected}
+}
+
+func (s *PEP440SpecifierSet) String() string {
+	return s.str
+}
+
+// allowsPreReleases returns true if the set contains the pre-releases within its limits
+func (s *PEP440SpecifierSet) allowsPreReleases() bool {
+	if s.includePrerelease {
+		return true
+	}
+	for _, spec := range s.specifiers {
+		if spec.allowsPreReleases() {
+			return true
+		}
+	}
+	return false
+}
+
+// Contains checks if the PEP 440 version v satisfies all the specifiers of the set. Pre-releases
+// are only contained if a specifier mentions one or the set was parsed with IncludePrerelease
+func (s *PEP440SpecifierSet) Contains(v *PEP440Version) bool {
+	if v.IsPreRelease() && !s.allowsPreReleases() {
+		return false
+	}
+	for _, spec := range s.specifiers {
+		if !spec.contains(v) {
+			return false
+		}
+	}
+	return true
+}
+
+// Matches checks if the set contains the PEP 440 version equivalent to v, in which the pre-release
+// is a PEP 440 label: "1.0.0-rc.1" is "1.0.0rc1". Versions whose pre-release is not a PEP 440
+// label, such as "1.0.0-foo", are not matched
+func (s *PEP440SpecifierSet) Matches(v *Version) bool {
+	pv := pep440VersionOf(v)
+	return pv != nil && s.Contains(pv)
+}
@@ -0,0 +1,187 @@
+package semver
+
+import "testing"
+
+// Ordered list from the test suite of PEP 440's reference implementation
+var pep440OrderedVersions = []string{
+	"1.0.dev456", "1.0a1", "1.0a2.dev456", "1.0a12.dev456", "1.0a12", "1.0b1.dev456", "1.0b2", "1.0b2.post345.dev456",
+	"1.0b2.post345", "1.0rc1.dev456", "1.0rc1", "1.0", "1.0+abc.5", "1.0+abc.7", "1.0+5", "1.0.post456.dev34",
+	"1.0.post456", "1.0.15", "1.1.dev1", "1!0.1",
+}
+
+func TestPEP440VersionOrdering(t *testing.T) {
+	for i, str := range pep440OrderedVersions {
+		v := MustParsePEP440Version(str)
+		for j, str2 := range pep440OrderedVersions {
+			expected := compareInt(i, j)
+			if res := v.Compare(MustParsePEP440Version(str2)); res != expected {
+				t.Errorf("Expected the comparison of PEP 440 versions %q and %q to be %d but got %d", str, str2, expected, res)
+			}
+		}
+	}
+}
+
+func TestPEP440VersionCanonical(t *testing.T) {
+	for str, expected := range map[string]string{
+		"1.0":                       "1.0",
+		"v1.0":                      "1.0",
+		" 1.0\n":                    "1.0",
+		"01.002.0":                  "1.2.0",
+		"1.0-ALPHA.1":               "1.0a1",
+		"1.0a":                      "1.0a0",
+		"1.0beta2":                  "1.0b2",
+		"1.0c1":                     "1.0rc1",
+		"1.0.pre1":                  "1.0rc1",
+		"1.0_preview_3":             "1.0rc3",
+		"1.0-1":                     "1.0.post1",
+		"1.0rev2":                   "1.0.post2",
+		"1.0-r":                     "1.0.post0",
+		"1.0.POST":                  "1.0.post0",
+		"1.0dev":                    "1.0.dev0",
+		"1.0-dev-2":                 "1.0.dev2",
+		"0!1.0":                     "1.0",
+		"1!2.0.post1.dev3":          "1!2.0.post1.dev3",
+		"1.0+Ubuntu-1_02":           "1.0+ubuntu.1.2",
+		"1.0rc1.post2.dev3":         "1.0rc1.post2.dev3",
+		"12345678901234567890123.0": "12345678901234567890123.0",
+	} {
+		v, err := ParsePEP440Version(str)
+		if err != nil {
+			t.Errorf("Expected %q to be a valid PEP 440 version but got %v", str, err)
+			continue
+		}
+		if res := v.Canonical(); res != expected {
+			t.Errorf("Expected the canonical form of PEP 440 version %q to be %q but got %q", str, expected, res)
+		}
+		if v.String() != str {
+			t.Errorf("Expected the string of PEP 440 version %q to be the original input but got %q", str, v)
+		}
+	}
+	for _, str := range []string{"", "1.", ".1", "1.0-foo", "1.0+", "1.0+a..b", "1.0.x", "a1.0", "1.0 rc1", "1!"} {
+		if _, err := ParsePEP440Version(str); err == nil {
+			t.Errorf("Expected PEP 440 version %q to be invalid", str)
+		}
+	}
+}
+
+func TestPEP440VersionLabels(t *testing.T) {
+	for str, expected := range map[string][3]bool{
+		"1.0":            {false, false, false},
+		"1.0a1":          {true, false, false},
+		"1.0.dev1":       {true, false, true},
+		"1.0.post1":      {false, true, false},
+		"1.0.post1.dev1": {true, true, true},
+		"1.0+local":      {false, false, false},
+	} {
+		v := MustParsePEP440Version(str)
+		if res := [3]bool{v.IsPreRelease(), v.IsPostRelease(), v.IsDevRelease()}; res != expected {
+			t.Errorf("Expected the labels of PEP 440 version %q to be %v but got %v", str, expected, res)
+		}
+	}
+}
+
+func TestSortPEP440Versions(t *testing.T) {
+	versions := []*PEP440Version{}
+	for i := len(pep440OrderedVersions) - 1; i >= 0; i-- {
+		versions = append(versions, MustParsePEP440Version(pep440OrderedVersions[i]))
+	}
+	SortPEP440Versions(versions)
+	for i, v := range versions {
+		if v.String() != pep440OrderedVersions[i] {
+			t.Errorf("Expected PEP 440 version %d to be %q but got %q", i, pep440OrderedVersions[i], v)
+		}
+	}
+}
+
+func TestPEP440SpecifierSet(t *testing.T) {
+	for str, data := range map[string]map[string]bool{
+		"~=1.4.2":        {"1.4.2": true, "1.4.10": true, "1.4.2.post1": true, "1.5.0": false, "1.4.1": false, "1.4.3rc1": false},
+		"~=2.2":          {"2.2": true, "2.9": true, "3.0": false, "2.1": false},
+		"~=1.4.5a4":      {"1.4.5a4": true, "1.4.5": true, "1.4.9b1": true, "1.5": false},
+		"==1.4.*":        {"1.4": true, "1.4.0": true, "1.4.9": true, "1.4.9+local": true, "1.40": false, "1.5": false, "1.4.1rc1": false},
+		"==1.4":          {"1.4": true, "1.4.0": true, "1.4+local": true, "1.4.0.post1": false, "1.4.1": false},
+		"==1.4+local":    {"1.4+local": true, "1.4": false, "1.4+other": false},
+		"!=1.5.0":        {"1.4": true, "1.5": false, "1.5.0+local": false, "1.5.1": true},
+		"!=1.5.*":        {"1.4": true, "1.5.3": false, "1.6": true},
+		">=1.0,<2.0":     {"1.0": true, "1.9.9": true, "2.0": false, "0.9": false, "2.0a1": false, "1.5.dev1": false},
+		">= 1.0 , < 2.0": {"1.0": true, "2.0": false},
+		"<2.0":           {"1.9": true, "2.0rc1": false, "2.0.dev1": false, "1.9+local": true},
+		"<2.0rc2":        {"2.0rc1": true, "2.0rc2": false, "1.9a1": true},
+		">1.7":           {"1.7.1": true, "1.7.0.post1": false, "1.7+local": false, "1.8.dev1": false},
+		">1.7.post2":     {"1.7.post3": true, "1.7.post2": false, "1.7.1": true},
+		"<=2.0":          {"2.0": true, "2.0+local": true, "2.0.post1": false},
+		"===1.0+Local":   {"1.0+local": true, "1.0+LOCAL": true, "1.0": false, "v1.0+local": false},
+		"==1!1.0":        {"1!1.0": true, "1.0": false},
+		">=1.0rc1":       {"1.0rc1": true, "1.5a1": true, "1.0b1": false, "1.0": true},
+		"":               {"1.0": true, "0.0.1": true, "1.0a1": false, "1.0.post1": true},
+	} {
+		s := MustParsePEP440SpecifierSet(str)
+		for v, expected := range data {
+			if s.Contains(MustParsePEP440Version(v)) != expected {
+				t.Errorf("Expected PEP 440 specifier set %q to contain %q: %v", str, v, expected)
+			}
+		}
+	}
+}
+
+func TestPEP440SpecifierSetPreReleases(t *testing.T) {
+	for str, data := range map[string]map[string]bool{
+		">=1.0":      {"2.0a1": true, "1.0.dev1": false, "2.0": true},
+		"<2.0":       {"1.9rc1": true, "2.0rc1": false},
+		"!=1.5":      {"1.5a1": true, "1.5": false},
+		"==1.4.*":    {"1.4.1rc1": true, "1.5rc1": false},
+		"~=1.0,<2.0": {"1.9.dev0": true, "2.0.dev0": false},
+	} {
+		s := MustParsePEP440SpecifierSet(str, IncludePrerelease)
+		for v, expected := range data {
+			if s.Contains(MustParsePEP440Version(v)) != expected {
+				t.Errorf("Expected PEP 440 specifier set %q to contain %q with IncludePrerelease: %v", str, v, expected)
+			}
+		}
+	}
+}
+
+func TestPEP440SpecifierSetMatches(t *testing.T) {
+	for str, data := range map[string]map[string]bool{
+		"~=1.4.2":         {"1.4.2": true, "1.4.9": true, "1.5.0": false},
+		">=1.0,<2.0":      {"1.2.3": true, "2.0.0": false, "1.5.0-rc.1": false, "1.0.0+build": true},
+		">=1.0.0rc1":      {"1.0.0-rc.1": true, "1.0.0-beta.1": false, "1.0.0-alpha": false, "1.2.0-dev.3": true},
+		">=1.0.0a1,<2.0":  {"1.5.0-foo": false, "1.5.0-1": false},
+		"==1.4.*,!=1.4.5": {"1.4.0": true, "1.4.5": false},
+	} {
+		var e Expression = MustParsePEP440SpecifierSet(str)
+		for v, expected := range data {
+			if e.Matches(MustParseVersion(v)) != expected {
+				t.Errorf("Expected PEP 440 specifier set %q to match %q: %v", str, v, expected)
+			}
+		}
+	}
+	if e := MustParsePEP440SpecifierSet(">=1.0", IncludePrerelease); !e.Matches(MustParseVersion("1.5.0-beta.2")) {
+		t.Errorf("Expected IncludePrerelease to be taken into account by Matches")
+	}
+}
+
+func TestPEP440SpecifierSetErrors(t *testing.T) {
+	for str, offset := range map[string]int{
+		"1.0":           0,
+		">=1.0,":        6,
+		">=1.0, 2.0":    7,
+		">=1.0,,<2.0":   6,
+		"=>1.0":         0,
+		">=":            2,
+		">= foo":        3,
+		">=1.0.*":       2,
+		"~=1":           2,
+		"~=1.0+local":   2,
+		"<1.0+local":    1,
+		"==1.0a1.*":     2,
+		"==1.0+local.*": 2,
+		"===":           3,
+		"== 1.0 rc1":    3,
+	} {
+		_, err := ParsePEP440SpecifierSet(str)
+		if pErr, ok := err.(*ParseError); !ok || pErr.Offset != offset {
+			t.Errorf("Expected PEP 440 specifier set %q to fail at offset %d but got %v", str, offset, err)
+		}
+	}
+}
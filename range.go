@@ -29,24 +29,6 @@ var rangedVersionRe = regexp.MustCompile(
 	),
 )
 
-var simpleRangeOperators = []string{`\^`, `~>`, `~`, `=`, `\<=`, `\>=`, `\<`, `\>`, ``}
-
-var simpleRangeExpr = regexp.MustCompile(fmt.Sprintf(
-	`((?P<rangeOp>%s)\s*(?P<version1>%s))`,
-	strings.Join(simpleRangeOperators, `|`), rangedVersionRe.String()))
-
-var rangeExpr = regexp.MustCompile(fmt.Sprintf(
-	`(?P<range>%s|%s)`,
-	hyphenRangeExpr.String(), simpleRangeExpr.String()))
-
-var hyphenRangeExpr = regexp.MustCompile(fmt.Sprintf(
-	`((?P<version1>%s)\s+(?P<rangeOp>\-)\s+(?P<version2>%s))`,
-	rangedVersionRe.String(), rangedVersionRe.String()))
-
-var versionExpr = regexp.MustCompile(fmt.Sprintf(
-	`(?P<version1>%s)`,
-	rangedVersionRe.String()))
-
 // GlobVersion defines a version supporting x-range elements
 type GlobVersion struct {
 	*Version
@@ -60,6 +42,29 @@ func (v *GlobVersion) IsFixed() bool {
 	return !v.anyMajor && !v.anyMinor && !v.anyPatch
 }
 
+// String returns the x-range, using "x" for its wildcard elements
+func (v *GlobVersion) String() string {
+	if v == nil || v.Version == nil {
+		return ""
+	}
+	list := []string{}
+	for i, any := range []bool{v.anyMajor, v.anyMinor, v.anyPatch} {
+		if any {
+			list = append(list, "x")
+		} else {
+			list = append(list, strconv.FormatInt(v.split()[i], 10))
+		}
+	}
+	s := strings.Join(list, ".")
+	if v.PreRelease != "" {
+		s += `-` + v.PreRelease
+	}
+	if v.Build != "" {
+		s += `+` + v.Build
+	}
+	return s
+}
+
 func newGlobVersion(major int64, minor int64, patch int64) *GlobVersion {
 	return &GlobVersion{
 		anyMajor: major < 0,
@@ -144,66 +149,23 @@ type Range struct {
 	AllowMinEquality bool
 	MaxVersion       *GlobVersion
 	AllowMaxEquality bool
+	// IncludePrerelease makes the range contain any pre-release version within its limits
+	IncludePrerelease bool
 }
 
 var infinity = int64(math.Inf(1))
 
 // RegExp returns a pair of regexps corresponding to the lower and higher limits
 // of the range. If a version string matches both regexps, is contained in the range.
+// It returns nil if the regexp cannot be compiled.
+//
+// Deprecated: Use Regexp, which returns a single anchored regexp
 func (r *Range) RegExp() []*regexp.Regexp {
-	result := make([]*regexp.Regexp, 2)
-	if v1 := r.MinVersion; v1 != nil {
-		list := []string{
-			fmt.Sprintf(`%d\.%d\.%s`, v1.Major, v1.Minor, gt(int(v1.Patch))),
-			fmt.Sprintf(`%d\.%s\.\d+`, v1.Major, gt(int(v1.Minor))),
-			fmt.Sprintf(`%s\.\d+\.\d+`, gt(int(v1.Major))),
-		}
-		if r.AllowMinEquality {
-			eqlStrComp := []string{}
-			for _, c := range []int64{v1.Major, v1.Minor, v1.Patch} {
-				if c < 0 {
-					eqlStrComp = append(eqlStrComp, `\d*`)
-				} else {
-					eqlStrComp = append(eqlStrComp, strconv.Itoa(int(c)))
-				}
-			}
-			list = append(list, strings.Join(eqlStrComp, `.`))
-		}
-		result[0] = regexp.MustCompile(fmt.Sprintf(`(?:(%s))`, strings.Join(list, `|`)))
-	} else {
-		result[0] = regexp.MustCompile(`.*`)
-	}
-	if v2 := r.MaxVersion; v2 != nil {
-		list := []string{}
-		if v2.Patch > 0 {
-			list = append(list, fmt.Sprintf(`%d\.%d\.%s`, v2.Major, v2.Minor, lt(int(v2.Patch))))
-		}
-		if v2.Minor > 0 {
-			list = append(list, fmt.Sprintf(`%d\.%s\.\d+`, v2.Major, lt(int(v2.Minor))))
-		}
-
-		if v2.Major > 0 {
-			list = append(list, fmt.Sprintf(`%s\.\d+\.\d+`, lt(int(v2.Major))))
-		} else if v2.Major < 0 {
-			// *.\d.\d is in-matcheable
-			list = append(list, fmt.Sprintf(`x\.x\.x`))
-		}
-		if r.AllowMaxEquality {
-			eqlStrComp := []string{}
-			for _, c := range []int64{v2.Major, v2.Minor, v2.Patch} {
-				if c < 0 {
-					eqlStrComp = append(eqlStrComp, `\d*`)
-				} else {
-					eqlStrComp = append(eqlStrComp, strconv.Itoa(int(c)))
-				}
-			}
-			list = append(list, strings.Join(eqlStrComp, `.`))
-		}
-		result[1] = regexp.MustCompile(fmt.Sprintf(`(?:(%s))`, strings.Join(list, `|`)))
-	} else {
-		result[1] = regexp.MustCompile(`.*`)
+	re, err := r.Regexp()
+	if err != nil {
+		return nil
 	}
-	return result
+	return []*regexp.Regexp{re, re}
 }
 
 // UpperLimit returns a version describing the upper limit of the range
@@ -232,8 +194,8 @@ func (r *Range) LowerLimit() *GlobVersion {
 
 // MustParseRange creates a Range from a semver string
 // It panics in case of error
-func MustParseRange(str string) *Range {
-	r, err := ParseRange(str)
+func MustParseRange(str string, opts ...ParseOption) *Range {
+	r, err := ParseRange(str, opts...)
 	if err != nil {
 		panic(err)
 	}
@@ -241,15 +203,28 @@ func MustParseRange(str string) *Range {
 }
 
 // ParseRange creates a Range from a semver string
-// It will return a non-nil error if it fails
-func ParseRange(str string) (*Range, error) {
-	matched, mapping := namedReEvaluate(rangeExpr, str)
-	if !matched {
-		return nil, fmt.Errorf("Malformed range expression %q", str)
+// It will return a non-nil error if it fails. Syntax errors are reported as a *ParseError.
+// As in npm, "~>" is an alias of "~", so "~>1.2" is ">=1.2.0 <1.3.0". It is not the pessimistic
+// operator of RubyGems, where "~> 1.2" is ">= 1.2, < 2": use ParseGemRequirement for gem requirements
+func ParseRange(str string, opts ...ParseOption) (*Range, error) {
+	p, err := newParser(str, opts...)
+	if err != nil {
+		return nil, err
 	}
-	v := MustParseGlobVersion(mapping["version1"])
-	operator := mapping["rangeOp"]
-	op := &Range{}
+	n, err := p.parseRange()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expectEOF("end of range"); err != nil {
+		return nil, err
+	}
+	return n.Range, nil
+}
+
+// newRange creates a Range from the provided operator and versions. v2 is only used
+// by hyphen ranges
+func newRange(operator string, v *GlobVersion, v2 *GlobVersion, c parseConfig) (*Range, error) {
+	op := &Range{IncludePrerelease: c.includePrerelease}
 
 	var maxVersion, minVersion *GlobVersion
 
@@ -259,14 +234,14 @@ func ParseRange(str string) (*Range, error) {
 
 	switch operator {
 	case `-`:
-		v2 := MustParseGlobVersion(mapping["version2"])
 		op.AllowMinEquality = true
 
 		if v2.patchPresent {
 			maxVersion = v2
 			op.AllowMaxEquality = true
 		} else {
-			maxVersion = v2.next()
+			next := v2.next()
+			maxVersion = op.exclusiveLimit(next.Major, next.Minor, next.Patch)
 			op.AllowMaxEquality = false
 		}
 	case `^`:
@@ -277,7 +252,7 @@ func ParseRange(str string) (*Range, error) {
 				break
 			}
 		}
-		maxVersion = newGlobVersion(d[0], d[1], d[2])
+		maxVersion = op.exclusiveLimit(d[0], d[1], d[2])
 		op.AllowMaxEquality = false
 	case `>`:
 		minVersion = v
@@ -292,6 +267,7 @@ func ParseRange(str string) (*Range, error) {
 	case `<=`:
 		minVersion = nil
 		op.AllowMaxEquality = true
+		maxVersion = v
 	case `=`:
 		fallthrough
 	case ``:
@@ -300,29 +276,56 @@ func ParseRange(str string) (*Range, error) {
 		op.AllowMaxEquality = true
 		op.AllowMinEquality = true
 	case `~>`:
+		// As in npm, "~>" is an alias of "~" (see ParseRange)
 		fallthrough
 	case `~`:
 		switch {
 		case v.minorPresent:
-			maxVersion = newGlobVersion(v.Major, v.Minor+1, 0)
+			maxVersion = op.exclusiveLimit(v.Major, v.Minor+1, 0)
 		default:
-			maxVersion = newGlobVersion(v.Major+1, 0, 0)
+			maxVersion = op.exclusiveLimit(v.Major+1, 0, 0)
 		}
 	default:
-		return nil, fmt.Errorf(`Unknown range operator ` + operator)
+		return nil, fmt.Errorf(`Unknown range operator %s`, operator)
 	}
 	op.MaxVersion = maxVersion
 	op.MinVersion = minVersion
+	if op.IncludePrerelease {
+		op.fixLimits()
+	}
 	return op, nil
 }
 
-// Contains checks if the provided version v is contained by the Range
-func (r *Range) Contains(v *Version) bool {
-	if (v.Greater(r.MinVersion) || (r.AllowMinEquality && v.Equal(r.MinVersion))) &&
-		(v.Less(r.MaxVersion) || (r.AllowMaxEquality && v.Equal(r.MaxVersion))) {
-		return true
+// exclusiveLimit returns the upper limit of caret, tilde and hyphen ranges. As in npm, it is the
+// lowest pre-release of the version if the range includes pre-releases, so "^1.2.3" does not
+// contain 2.0.0-rc.1
+func (r *Range) exclusiveLimit(major, minor, patch int64) *GlobVersion {
+	if r.IncludePrerelease {
+		return &GlobVersion{Version: preReleaseFloor(major, minor, patch)}
+	}
+	return newGlobVersion(major, minor, patch)
+}
+
+// fixLimits replaces the x-range limits of a range including pre-releases with the lowest
+// pre-releases they bound, as npm does: "1.2.x" is ">=1.2.0-0 <1.3.0-0"
+func (r *Range) fixLimits() {
+	s := r.exactVersionSet()
+	if len(s) == 0 {
+		return
+	}
+	if v := r.MinVersion; v != nil && !v.IsFixed() && !v.anyMajor {
+		r.MinVersion, r.AllowMinEquality = &GlobVersion{Version: s[0].min.v}, s[0].min.inclusive
+	}
+	if v := r.MaxVersion; v != nil && !v.IsFixed() && !v.anyMajor {
+		r.MaxVersion, r.AllowMaxEquality = &GlobVersion{Version: s[0].max.v}, s[0].max.inclusive
 	}
-	return false
+}
+
+// Contains checks if the provided version v is contained by the Range.
+// Following npm semantics, a pre-release version is only contained if one of the range limits
+// is a pre-release of the same major, minor and patch, unless IncludePrerelease is set
+func (r *Range) Contains(v *Version) bool {
+	return r.evaluate(v, false)
 }
 
 // Matches is equivalent to Contains and is provided to satisfy the Expression interface (a range is a simple expression)
@@ -330,6 +333,25 @@ func (r *Range) Matches(v *Version) bool {
 	return r.Contains(v)
 }
 
-func (r *Range) evaluate(v *Version) bool {
-	return r.Contains(v)
+func (r *Range) evaluate(v *Version, preReleaseAllowed bool) bool {
+	if v.PreRelease != "" && !preReleaseAllowed && !r.allowsPreRelease(v) {
+		return false
+	}
+	return (v.Greater(r.MinVersion) || (r.AllowMinEquality && v.Equal(r.MinVersion))) &&
+		(v.Less(r.MaxVersion) || (r.AllowMaxEquality && v.Equal(r.MaxVersion)))
+}
+
+// allowsPreRelease returns true if the pre-release version v is allowed by the range: either
+// any pre-release is or one of the limits is a pre-release of the same major, minor and patch
+func (r *Range) allowsPreRelease(v *Version) bool {
+	if r.IncludePrerelease {
+		return true
+	}
+	for _, limit := range []*GlobVersion{r.MinVersion, r.MaxVersion} {
+		if limit != nil && limit.PreRelease != "" && limit.IsFixed() &&
+			limit.Major == v.Major && limit.Minor == v.Minor && limit.Patch == v.Patch {
+			return true
+		}
+	}
+	return false
 }
@@ -154,6 +154,13 @@ var rangeTestBattery = map[string]map[string]bool{
 		"1.2.6": false,
 		"1.1.0": false,
 	},
+	"<=1.2.7": {
+		"1.2.7": true,
+		"1.2.6": true,
+		"0.0.1": true,
+		"1.2.8": false,
+		"2.0.0": false,
+	},
 	">*.*.*": {
 		"1.0.2": false,
 		"0.0.0": false,
@@ -172,6 +179,126 @@ var rangeTestBattery = map[string]map[string]bool{
 	},
 }
 
+var preReleaseTestBattery = map[string]map[string]bool{
+	">=1.2.3-beta.2": {
+		"1.2.3-beta.2": true,
+		"1.2.3-beta.3": true,
+		"1.2.3-beta.1": false,
+		"1.2.3":        true,
+		"3.0.0-alpha":  false,
+		"3.0.0":        true,
+	},
+	"^1.2.3": {
+		"1.2.3-rc.1": false,
+		"1.4.0-beta": false,
+		"2.0.0-rc.1": false,
+		"1.4.0":      true,
+	},
+	"1.2.3-rc.1 - 1.2.3": {
+		"1.2.3-rc.1": true,
+		"1.2.3-rc.2": true,
+		"1.2.3":      true,
+		"1.2.2-rc.1": false,
+	},
+	"<1.2.3-rc.5": {
+		"1.2.3-rc.4": true,
+		"1.2.3-rc.5": false,
+		"1.2.2-rc.4": false,
+		"1.2.2":      true,
+	},
+	"=1.2.3-rc.1": {
+		"1.2.3-rc.1": true,
+		"1.2.3-rc.2": false,
+		"1.2.3":      false,
+	},
+	"*": {
+		"1.2.3-rc.1": false,
+		"1.2.3":      true,
+	},
+}
+
+// Same battery, parsed with the IncludePrerelease option
+var includePreReleaseTestBattery = map[string]map[string]bool{
+	">=1.2.3-beta.2": {
+		"1.2.3-beta.3": true,
+		"1.2.3-beta.1": false,
+		"3.0.0-alpha":  true,
+	},
+	"^1.2.3": {
+		"1.2.3-rc.1": false,
+		"1.4.0-beta": true,
+		"2.0.0-rc.1": false,
+		"2.0.0-0":    false,
+	},
+	"~1.2.3": {
+		"1.2.9-rc.1": true,
+		"1.3.0-rc.1": false,
+	},
+	"~1.2": {
+		"1.2.0-rc.1": true,
+		"1.3.0-rc.1": false,
+	},
+	"1.2.x": {
+		"1.2.0-rc.1": true,
+		"1.3.0-rc.1": false,
+	},
+	"<=1.2": {
+		"1.2.9-rc.1": true,
+		"1.3.0-rc.1": false,
+	},
+	">1.2": {
+		"1.2.9-rc.1": false,
+		"1.3.0-rc.1": true,
+	},
+	"1.2.3 - 1.4": {
+		"1.4.9-rc.1": true,
+		"1.5.0-rc.1": false,
+	},
+	"*": {
+		"1.2.3-rc.1": true,
+	},
+}
+
+// Pre-release limits of the ranges of includePreReleaseTestBattery, as printed by npm
+var includePreReleaseStringTestBattery = map[string]string{
+	"^1.2.3":      ">=1.2.3 <2.0.0-0",
+	"^1.2":        ">=1.2.0-0 <2.0.0-0",
+	"~1.2.3":      ">=1.2.3 <1.3.0-0",
+	"~1.2":        ">=1.2.0-0 <1.3.0-0",
+	"1.2.x":       ">=1.2.0-0 <1.3.0-0",
+	"<=1.2":       "<1.3.0-0",
+	">1.2":        ">=1.3.0-0",
+	"1.2.3 - 1.4": ">=1.2.3 <1.5.0-0",
+	"*":           "*",
+}
+
+func TestRangePreReleases(t *testing.T) {
+	for _, test := range []struct {
+		battery map[string]map[string]bool
+		opts    []ParseOption
+	}{
+		{battery: preReleaseTestBattery},
+		{battery: includePreReleaseTestBattery, opts: []ParseOption{IncludePrerelease}},
+	} {
+		for rangeStr, data := range test.battery {
+			r := MustParseRange(rangeStr, test.opts...)
+			for v, result := range data {
+				if r.Contains(MustParseVersion(v)) != result {
+					t.Errorf("Expected %v of %v to evaluate to %v", rangeStr, v, result)
+				}
+			}
+		}
+	}
+}
+
+func TestRangeIncludePrereleaseLimits(t *testing.T) {
+	for rangeStr, expected := range includePreReleaseStringTestBattery {
+		if res := MustParseRange(rangeStr, IncludePrerelease).String(); res != expected {
+			t.Errorf("Expected %q including pre-releases to be %q but got %q", rangeStr, expected, res)
+		}
+	}
+}
+
 func testRange(t *testing.T, rangeStr string, battery map[string]bool) {
 	r := MustParseRange(rangeStr)
 	re := r.RegExp()
@@ -180,8 +307,7 @@ func testRange(t *testing.T, rangeStr string, battery map[string]bool) {
 		if r.Contains(nv) != result {
 			t.Errorf("Expected %v of %v to evaluate to %v", rangeStr, v, result)
 		}
-		if re[0].MatchString(nv.String()) &&
-			re[1].MatchString(nv.String()) != result {
+		if (re[0].MatchString(nv.String()) && re[1].MatchString(nv.String())) != result {
 			t.Errorf("Expected %v of %v to evaluate to %v (Using regexp checks)", rangeStr, v, result)
 		}
 	}
@@ -0,0 +1,546 @@
+package semver
+
+import (
+	"fmt"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Regular expressions describing the SemVer 2.0 syntax
+const (
+	numericRe    = `(?:0|[1-9]\d*)`
+	alnumRe      = `[0-9A-Za-z-]*[A-Za-z-][0-9A-Za-z-]*`
+	identifierRe = `(?:0|[1-9]\d*|` + alnumRe + `)`
+	buildRe      = `(?:\+[0-9A-Za-z-]+(?:\.[0-9A-Za-z-]+)*)?`
+)
+
+// identifierChars contains the characters allowed in identifiers, in ASCII order
+const identifierChars = "-0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+// regexAlternatives is a list of alternative regular expressions. An empty list does not match anything
+type regexAlternatives []string
+
+// group returns a single regular expression matching any of the alternatives
+func (a regexAlternatives) group() string {
+	if len(a) == 1 {
+		return a[0]
+	}
+	return `(?:` + strings.Join(a, `|`) + `)`
+}
+
+// regexSeq returns the concatenation of the provided alternatives
+func regexSeq(parts ...regexAlternatives) regexAlternatives {
+	s := ""
+	for _, p := range parts {
+		if len(p) == 0 {
+			return nil
+		}
+		s += p.group()
+	}
+	return regexAlternatives{s}
+}
+
+func regexLit(s string) regexAlternatives {
+	return regexAlternatives{s}
+}
+
+// digitsRe matches n digits
+func digitsRe(n int) string {
+	switch n {
+	case 0:
+		return ""
+	case 1:
+		return `\d`
+	default:
+		return fmt.Sprintf(`\d{%d}`, n)
+	}
+}
+
+// charClass returns a regular expression matching any of the provided characters
+func charClass(chars string) string {
+	if len(chars) == 1 {
+		return regexp.QuoteMeta(chars)
+	}
+	s := ""
+	for i := 0; i < len(chars); {
+		j := i
+		for j+1 < len(chars) && chars[j+1] == chars[j]+1 {
+			j++
+		}
+		first, last := string(chars[i]), string(chars[j])
+		if first == "-" {
+			first = `\-`
+		}
+		switch {
+		case i == j:
+			s += first
+		case j == i+1:
+			s += first + last
+		default:
+			s += first + "-" + last
+		}
+		i = j + 1
+	}
+	return "[" + s + "]"
+}
+
+// digitClass returns a regular expression matching the digits in [from, to], or "" if there are none
+func digitClass(from, to byte) string {
+	if from > to {
+		return ""
+	}
+	return charClass(identifierChars[strings.IndexByte(identifierChars, from) : strings.IndexByte(identifierChars, to)+1])
+}
+
+// sameLengthAtLeast returns the numbers with the same digits as s which are greater or equal than it
+func sameLengthAtLeast(s string) regexAlternatives {
+	a := regexAlternatives{s}
+	for i := len(s) - 1; i >= 0; i-- {
+		if c := digitClass(s[i]+1, '9'); c != "" {
+			a = append(a, s[:i]+c+digitsRe(len(s)-i-1))
+		}
+	}
+	return a
+}
+
+// sameLengthAtMost returns the numbers with the same digits as s which are less or equal than it.
+// If leading is true, s is the start of the number, so it cannot start with a zero
+func sameLengthAtMost(s string, leading bool) regexAlternatives {
+	a := regexAlternatives{s}
+	for i := len(s) - 1; i >= 0; i-- {
+		low := byte('0')
+		if leading && i == 0 && len(s) > 1 {
+			low = '1'
+		}
+		if s[i] > low {
+			a = append(a, s[:i]+digitClass(low, s[i]-1)+digitsRe(len(s)-i-1))
+		}
+	}
+	return a
+}
+
+// numericRange returns the numbers in [lo, hi]. An empty hi means the range is not bounded
+func numericRange(lo, hi string) regexAlternatives {
+	switch {
+	case hi == "":
+		return append(sameLengthAtLeast(lo), `[1-9]`+fmt.Sprintf(`\d{%d,}`, len(lo)))
+	case len(lo) < len(hi):
+		a := sameLengthAtLeast(lo)
+		for n := len(lo) + 1; n < len(hi); n++ {
+			a = append(a, `[1-9]`+digitsRe(n-1))
+		}
+		return append(a, sameLengthAtMost(hi, true)...)
+	}
+	i := 0
+	for i < len(lo) && lo[i] == hi[i] {
+		i++
+	}
+	if i == len(lo) {
+		return regexAlternatives{lo}
+	}
+	a := regexSeq(regexLit(lo[:i+1]), sameLengthAtLeast(lo[i+1:]))
+	if c := digitClass(lo[i]+1, hi[i]-1); c != "" {
+		a = append(a, lo[:i]+c+digitsRe(len(lo)-i-1))
+	}
+	return append(a, regexSeq(regexLit(hi[:i+1]), sameLengthAtMost(hi[i+1:], false))...)
+}
+
+// addNumeric returns the numeric identifier n plus delta
+func addNumeric(n string, delta int64) string {
+	i, _ := new(big.Int).SetString(n, 10)
+	return i.Add(i, big.NewInt(delta)).String()
+}
+
+func compareNumeric(n1, n2 string) int {
+	return compareIdentifiers(addNumeric(n1, 0), addNumeric(n2, 0))
+}
+
+// numericBetween returns the numbers between lo and hi, both excluded. An empty lo or hi means
+// the range is not bounded on that side
+func numericBetween(lo, hi string) regexAlternatives {
+	from, to := "0", ""
+	if lo != "" {
+		from = addNumeric(lo, 1)
+	}
+	if hi != "" {
+		if compareNumeric(hi, "0") == 0 {
+			return nil
+		}
+		to = addNumeric(hi, -1)
+		if compareNumeric(from, to) > 0 {
+			return nil
+		}
+	}
+	return numericRange(from, to)
+}
+
+// stringPiece describes a set of strings: a literal prefix, optionally followed by one of the
+// characters in class and, if tail is true, any number of identifier characters
+type stringPiece struct {
+	prefix string
+	class  string
+	tail   bool
+}
+
+func charsBetween(lo, hi byte) string {
+	s := ""
+	for i := 0; i < len(identifierChars); i++ {
+		if c := identifierChars[i]; c > lo && c < hi {
+			s += string(c)
+		}
+	}
+	return s
+}
+
+func prefixPieces(prefix string, pieces []stringPiece) []stringPiece {
+	for i := range pieces {
+		pieces[i].prefix = prefix + pieces[i].prefix
+	}
+	return pieces
+}
+
+// stringsGreater returns the strings sorting after s
+func stringsGreater(s string) []stringPiece {
+	pieces := []stringPiece{}
+	for i := 0; i < len(s); i++ {
+		if c := charsBetween(s[i], 0xff); c != "" {
+			pieces = append(pieces, stringPiece{prefix: s[:i], class: c, tail: true})
+		}
+	}
+	return append(pieces, stringPiece{prefix: s, class: identifierChars, tail: true})
+}
+
+// stringsLess returns the non-empty strings sorting before s
+func stringsLess(s string) []stringPiece {
+	pieces := []stringPiece{}
+	for i := 0; i < len(s); i++ {
+		if c := charsBetween(0, s[i]); c != "" {
+			pieces = append(pieces, stringPiece{prefix: s[:i], class: c, tail: true})
+		}
+		if i > 0 {
+			pieces = append(pieces, stringPiece{prefix: s[:i]})
+		}
+	}
+	return pieces
+}
+
+// stringsBetween returns the strings sorting between lo and hi, both excluded
+func stringsBetween(lo, hi string) []stringPiece {
+	if strings.HasPrefix(hi, lo) {
+		return prefixPieces(lo, stringsLess(hi[len(lo):]))
+	}
+	i := 0
+	for lo[i] == hi[i] {
+		i++
+	}
+	pieces := prefixPieces(lo[:i+1], stringsGreater(lo[i+1:]))
+	if c := charsBetween(lo[i], hi[i]); c != "" {
+		pieces = append(pieces, stringPiece{prefix: lo[:i], class: c, tail: true})
+	}
+	pieces = append(pieces, prefixPieces(hi[:i+1], stringsLess(hi[i+1:]))...)
+	if len(hi) > i+1 {
+		pieces = append(pieces, stringPiece{prefix: hi[:i+1]})
+	}
+	return pieces
+}
+
+func hasNonDigit(s string) bool {
+	return s != "" && !isNumeric(s)
+}
+
+// alphanumeric returns the regular expressions matching the alphanumeric identifiers (containing
+// at least a non-digit character) described by pieces
+func alphanumeric(pieces []stringPiece) regexAlternatives {
+	a := regexAlternatives{}
+	for _, p := range pieces {
+		prefix := regexp.QuoteMeta(p.prefix)
+		if p.class == "" {
+			if hasNonDigit(p.prefix) {
+				a = append(a, prefix)
+			}
+			continue
+		}
+		digits, others := "", ""
+		for i := 0; i < len(p.class); i++ {
+			if c := p.class[i]; c >= '0' && c <= '9' {
+				digits += string(c)
+			} else {
+				others += string(c)
+			}
+		}
+		tail := ""
+		if p.tail {
+			tail = `[0-9A-Za-z-]*`
+		}
+		if others != "" {
+			a = append(a, prefix+charClass(others)+tail)
+		}
+		switch {
+		case digits == "":
+		case hasNonDigit(p.prefix):
+			a = append(a, prefix+charClass(digits)+tail)
+		case p.tail:
+			a = append(a, prefix+charClass(digits)+alnumRe)
+		}
+	}
+	return a
+}
+
+// identifiersBetween returns the pre-release identifiers sorting between lo and hi, both excluded.
+// An empty lo or hi means the range is not bounded on that side
+func identifiersBetween(lo, hi string) regexAlternatives {
+	switch {
+	case lo == "" && hi == "":
+		return regexLit(identifierRe)
+	case hi == "":
+		if isNumeric(lo) {
+			return append(numericBetween(lo, ""), alnumRe)
+		}
+		return alphanumeric(stringsGreater(lo))
+	case isNumeric(hi):
+		return numericBetween(lo, hi)
+	case lo == "":
+		return append(regexAlternatives{numericRe}, alphanumeric(stringsLess(hi))...)
+	case isNumeric(lo):
+		return append(numericBetween(lo, ""), alphanumeric(stringsLess(hi))...)
+	default:
+		return alphanumeric(stringsBetween(lo, hi))
+	}
+}
+
+// regexElement is one of the elements compared to sort versions: the major, minor and patch
+// numbers, the pre-release identifiers or the end of the version
+type regexElement struct {
+	value string
+	end   bool
+}
+
+func regexElements(v *Version) []regexElement {
+	elements := []regexElement{}
+	for _, n := range v.split() {
+		elements = append(elements, regexElement{value: strconv.FormatInt(n, 10)})
+	}
+	if v.PreRelease != "" {
+		for _, id := range strings.Split(v.PreRelease, ".") {
+			elements = append(elements, regexElement{value: id})
+		}
+	}
+	return append(elements, regexElement{end: true})
+}
+
+// regexBuilder builds the regular expressions matching intervals of either release or pre-release versions.
+// Elements are identified by their position: 0 to 2 for major, minor and patch and 3 for the first
+// pre-release identifier. The end of the version sorts after any pre-release identifier in position 3
+// (releases have higher precedence), and before them in any later position
+type regexBuilder struct {
+	preReleases bool
+}
+
+func (b regexBuilder) separator(pos int) string {
+	switch {
+	case pos == 0:
+		return ""
+	case pos == 3:
+		return "-"
+	default:
+		return `\.`
+	}
+}
+
+// any returns the regular expression matching any elements starting at pos
+func (b regexBuilder) any(pos int) regexAlternatives {
+	switch {
+	case pos < 3:
+		return regexSeq(regexLit(b.separator(pos)+numericRe), b.any(pos+1))
+	case pos > 3:
+		return regexLit(`(?:\.` + identifierRe + `)*`)
+	case b.preReleases:
+		return regexLit(`-` + identifierRe + `(?:\.` + identifierRe + `)*`)
+	default:
+		return regexLit("")
+	}
+}
+
+// equal returns the regular expression matching the element e in pos
+func (b regexBuilder) equal(pos int, e regexElement) regexAlternatives {
+	switch {
+	case e.end && pos == 3 && b.preReleases:
+		return nil
+	case e.end:
+		return regexLit("")
+	case pos == 3 && !b.preReleases:
+		return nil
+	default:
+		return regexLit(b.separator(pos) + regexp.QuoteMeta(e.value))
+	}
+}
+
+// between returns the regular expression matching any element in pos sorting between lo and hi,
+// followed by any other elements. A nil element means the range is not bounded on that side
+func (b regexBuilder) between(pos int, lo, hi *regexElement) regexAlternatives {
+	loValue, hiValue := "", ""
+	if lo != nil {
+		loValue = lo.value
+	}
+	if hi != nil {
+		hiValue = hi.value
+	}
+	switch {
+	case pos < 3:
+		return regexSeq(regexLit(b.separator(pos)), numericBetween(loValue, hiValue), b.any(pos+1))
+	case lo != nil && lo.end && pos == 3:
+		// Nothing sorts after the end of a release
+		return nil
+	case pos == 3 && !b.preReleases:
+		// Releases end before the pre-release identifiers, sorting after them
+		if hi == nil {
+			return regexLit("")
+		}
+		return nil
+	case pos == 3:
+		if hi != nil && hi.end {
+			hiValue = ""
+		}
+		return regexSeq(regexLit("-"), identifiersBetween(loValue, hiValue), b.any(pos+1))
+	default:
+		var a regexAlternatives
+		if lo == nil && hi != nil && !hi.end {
+			a = regexLit("")
+		}
+		if hi != nil && hi.end {
+			return a
+		}
+		if lo != nil && lo.end {
+			loValue = ""
+		}
+		return append(a, regexSeq(regexLit(`\.`), identifiersBetween(loValue, hiValue), b.any(pos+1))...)
+	}
+}
+
+// atLeast returns the regular expression matching the elements starting at pos which sort after
+// the elements of v (or are equal to them, if inclusive)
+func (b regexBuilder) atLeast(pos int, v []regexElement, inclusive bool) regexAlternatives {
+	a := regexAlternatives{}
+	for i := pos; i < len(v); i++ {
+		prefix := regexLit("")
+		for j := pos; j < i; j++ {
+			prefix = regexSeq(prefix, b.equal(j, v[j]))
+		}
+		a = append(a, regexSeq(prefix, b.between(i, &v[i], nil))...)
+	}
+	if inclusive {
+		a = append(a, b.equalAll(pos, v)...)
+	}
+	return a
+}
+
+// atMost returns the regular expression matching the elements starting at pos which sort before
+// the elements of v (or are equal to them, if inclusive)
+func (b regexBuilder) atMost(pos int, v []regexElement, inclusive bool) regexAlternatives {
+	a := regexAlternatives{}
+	for i := pos; i < len(v); i++ {
+		prefix := regexLit("")
+		for j := pos; j < i; j++ {
+			prefix = regexSeq(prefix, b.equal(j, v[j]))
+		}
+		a = append(a, regexSeq(prefix, b.between(i, nil, &v[i]))...)
+	}
+	if inclusive {
+		a = append(a, b.equalAll(pos, v)...)
+	}
+	return a
+}
+
+func (b regexBuilder) equalAll(pos int, v []regexElement) regexAlternatives {
+	a := regexLit("")
+	for i := pos; i < len(v); i++ {
+		a = regexSeq(a, b.equal(i, v[i]))
+	}
+	return a
+}
+
+// interval returns the regular expression matching the versions in i
+func (b regexBuilder) interval(i interval) regexAlternatives {
+	unboundedBelow := i.min.inclusive && compareVersions(i.min.v, lowestPreRelease()) == 0
+	switch {
+	case i.max.v == nil && unboundedBelow:
+		return b.any(0)
+	case i.max.v == nil:
+		return b.atLeast(0, regexElements(i.min.v), i.min.inclusive)
+	case unboundedBelow:
+		return b.atMost(0, regexElements(i.max.v), i.max.inclusive)
+	}
+	lo, hi := regexElements(i.min.v), regexElements(i.max.v)
+	k := 0
+	for k < len(lo) && k < len(hi) && lo[k] == hi[k] {
+		k++
+	}
+	if k == len(lo) {
+		// Both limits are equal
+		if i.min.inclusive && i.max.inclusive {
+			return b.equalAll(0, lo)
+		}
+		return nil
+	}
+	a := b.between(k, &lo[k], &hi[k])
+	if !lo[k].end {
+		a = append(a, regexSeq(b.equal(k, lo[k]), b.atLeast(k+1, lo, i.min.inclusive))...)
+	} else if i.min.inclusive {
+		a = append(a, b.equal(k, lo[k])...)
+	}
+	if !hi[k].end {
+		a = append(a, regexSeq(b.equal(k, hi[k]), b.atMost(k+1, hi, i.max.inclusive))...)
+	} else if i.max.inclusive {
+		a = append(a, b.equal(k, hi[k])...)
+	}
+	return regexSeq(b.equalAll(0, lo[:k]), a)
+}
+
+// regexp returns an anchored regular expression matching the versions in s. It fails if the
+// expression exceeds the limits of the regexp package, such as a repeat count above 1000
+func (s splitSet) regexp() (*regexp.Regexp, error) {
+	a := regexAlternatives{}
+	for _, b := range []struct {
+		regexBuilder
+		set versionSet
+	}{{regexBuilder{preReleases: false}, s.releases}, {regexBuilder{preReleases: true}, s.preReleases}} {
+		for _, i := range b.set {
+			a = append(a, b.interval(i)...)
+		}
+	}
+	if len(a) == 0 {
+		// Nothing can match an empty class
+		return regexp.Compile(`^[^\x00-\x{10FFFF}]$`)
+	}
+	return regexp.Compile(`^` + a.group() + buildRe + `$`)
+}
+
+// Regexp returns an anchored regular expression matching exactly the strings of the versions
+// accepted by e, including their optional build metadata. Only valid SemVer 2.0 strings are
+// matched: versions with leading zeros, prefixes ("v1.2.3") or missing elements ("1.2") are not.
+// It supports expressions returned by ParseExpr and ranges, and fails with any other type or if
+// the limits of e need a regular expression too large to compile
+func Regexp(e Expression) (*regexp.Regexp, error) {
+	switch x := e.(type) {
+	case *Expr:
+		return x.Regexp()
+	case *Range:
+		return x.Regexp()
+	default:
+		return nil, fmt.Errorf("unsupported expression type: %T", e)
+	}
+}
+
+// Regexp returns an anchored regular expression matching exactly the strings of the versions
+// accepted by the expression
+func (e *Expr) Regexp() (*regexp.Regexp, error) {
+	return exactSetOf(e.AST(), e.includePrerelease).regexp()
+}
+
+// Regexp returns an anchored regular expression matching exactly the strings of the versions
+// contained in the range
+func (r *Range) Regexp() (*regexp.Regexp, error) {
+	return exactSetOf(&RangeNode{Range: r}, false).regexp()
+}
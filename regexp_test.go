@@ -0,0 +1,86 @@
+package semver
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func TestRegexpMatchesExpressions(t *testing.T) {
+	r := rand.New(rand.NewSource(42))
+	candidates := []string{"0.0.0", "0.0.0-0", "1.2.3", "1.2.3-rc.1", "1.2.3-rc.1+b", "2.0.0-0", "10.100.1000"}
+	for i := 0; i < 3000; i++ {
+		candidates = append(candidates, randomVersionString(r))
+	}
+	for _, str := range exactSetTestExpressions {
+		for _, opts := range [][]ParseOption{nil, {IncludePrerelease}} {
+			e := MustParseExpr(str, opts...)
+			re, err := Regexp(e)
+			if err != nil {
+				t.Fatalf("Expected the regexp of %q to be built but got %v", str, err)
+			}
+			for _, v := range candidates {
+				if expected := e.Matches(MustParseVersion(v)); re.MatchString(v) != expected {
+					t.Errorf("Expected the regexp of %q (include pre-releases: %v) to match %q: %v (%v)",
+						str, opts != nil, v, expected, re)
+				}
+			}
+		}
+	}
+}
+
+func TestRegexpMatchesRanges(t *testing.T) {
+	for rangeStr, data := range rangeTestBattery {
+		re, err := MustParseRange(rangeStr).Regexp()
+		if err != nil {
+			t.Errorf("Expected the regexp of %q to be built but got %v", rangeStr, err)
+			continue
+		}
+		for v, result := range data {
+			if s := MustParseVersion(v).String(); re.MatchString(s) != result {
+				t.Errorf("Expected the regexp of %q to match %q: %v (%v)", rangeStr, s, result, re)
+			}
+		}
+	}
+}
+
+func TestRegexpSyntax(t *testing.T) {
+	re, err := MustParseExpr("*", IncludePrerelease).(*Expr).Regexp()
+	if err != nil {
+		t.Fatalf("Expected the regexp of %q to be built but got %v", "*", err)
+	}
+	for v, result := range map[string]bool{
+		"1.2.3":                  true,
+		"1.2.3-rc.1+build.2":     true,
+		"1.2.3-0a.-.Z":           true,
+		"01.2.3":                 false,
+		"1.2.3-01":               false,
+		"1.2":                    false,
+		"v1.2.3":                 false,
+		" 1.2.3":                 false,
+		"1.2.3-":                 false,
+		"1.2.3-rc..1":            false,
+		"1.2.3+":                 false,
+		"1.2.3-rc.1_2":           false,
+		"1.2.3\n":                false,
+		"version 1.2.3 deployed": false,
+	} {
+		if re.MatchString(v) != result {
+			t.Errorf("Expected %q to match %v (%v)", v, result, re)
+		}
+	}
+	if re, err := Regexp(MustParseExpr(">2 <1")); err != nil || re.MatchString("1.5.0") || re.MatchString("") {
+		t.Errorf("Expected the regexp of an empty expression to never match but got %v (%v)", re, err)
+	}
+	if _, err := Regexp(MustParseGemRequirement("~> 1.2")); err == nil {
+		t.Errorf("Expected the regexp of an unsupported expression to fail")
+	}
+	// The numeric identifier needs a repeat count above the limit of the regexp package
+	str := ">=1.0.0-" + strings.Repeat("9", 1001)
+	if _, err := Regexp(MustParseExpr(str)); err == nil {
+		t.Errorf("Expected the regexp of %q to fail", str)
+	}
+	if res := MustParseRange(str).RegExp(); res != nil {
+		t.Errorf("Expected the deprecated regexps of %q to be nil but got %v", str, res)
+	}
+}
@@ -1,6 +1,12 @@
 package semver
 
-import "fmt"
+import (
+	"fmt"
+	"regexp"
+)
+
+// globVersionRe matches the strings made only of an x-range version
+var globVersionRe = regexp.MustCompile(`^` + rangedVersionRe.String() + `\s*$`)
 
 // Valid checks if a provided string is a valid version
 func Valid(str string) bool {
@@ -47,29 +53,86 @@ func Satisfies(version interface{}, expr interface{}) (bool, error) {
 	return e.Matches(v), nil
 }
 
-// ONLY VERSIONS FOR NOW (to be implemented: ranges and GlobVersion )
+// toComparable converts e into a *Version or a non-fixed *GlobVersion
+func toComparable(e Comparable) (Comparable, error) {
+	switch v := e.(type) {
+	case *Version:
+		if v == nil {
+			return nil, fmt.Errorf("nil version")
+		}
+		return v, nil
+	case *GlobVersion:
+		if v == nil {
+			return nil, fmt.Errorf("nil version")
+		}
+		if v.IsFixed() {
+			return v.Version, nil
+		}
+		return v, nil
+	case string:
+		if !globVersionRe.MatchString(v) {
+			return nil, fmt.Errorf("malformed version string %q", v)
+		}
+		g, err := ParseGlobVersion(v)
+		if err != nil {
+			return nil, err
+		}
+		if g.IsFixed() {
+			return ParseVersion(v)
+		}
+		return g, nil
+	default:
+		return nil, fmt.Errorf("unknown element type: %T", v)
+	}
+}
+
+// compareElements compares e1 and e2 using cmp. If e1 is an x-range, the elements are swapped and
+// compared using reverse instead. Elements that cannot be compared (nil, unknown types or two
+// x-ranges) are never equal, less or greater than others
+func compareElements(e1 Comparable, e2 Comparable, cmp func(v *Version, c Comparable) bool, reverse func(v *Version, c Comparable) bool) bool {
+	c1, err := toComparable(e1)
+	if err != nil {
+		return false
+	}
+	c2, err := toComparable(e2)
+	if err != nil {
+		return false
+	}
+	if v, ok := c1.(*Version); ok {
+		return cmp(v, c2)
+	}
+	if v, ok := c2.(*Version); ok {
+		return reverse(v, c1)
+	}
+	return false
+}
 
-// Greater checks if element e1 is greater than e2
+// Greater checks if element e1 is greater than e2. Elements can be versions,
+// x-range versions or their string representation
 func Greater(v1 Comparable, v2 Comparable) bool {
-	return v1.(*Version).Greater(v2.(*Version))
+	return compareElements(v1, v2, (*Version).Greater, (*Version).Less)
 }
 
-// Less checks if element e1 is less than e2
+// Less checks if element e1 is less than e2. Elements can be versions,
+// x-range versions or their string representation
 func Less(v1 Comparable, v2 Comparable) bool {
-	return v1.(*Version).Less(v2.(*Version))
+	return compareElements(v1, v2, (*Version).Less, (*Version).Greater)
 }
 
-// GreaterOrEqual checks if element e1 is greater or equal than e2
+// GreaterOrEqual checks if element e1 is greater or equal than e2. Elements can be versions,
+// x-range versions or their string representation
 func GreaterOrEqual(v1 Comparable, v2 Comparable) bool {
-	return v1.(*Version).GreaterOrEqual(v2.(*Version))
+	return compareElements(v1, v2, (*Version).GreaterOrEqual, (*Version).LessOrEqual)
 }
 
-// LessOrEqual checks if element e1 is less or equal to e2
+// LessOrEqual checks if element e1 is less or equal to e2. Elements can be versions,
+// x-range versions or their string representation
 func LessOrEqual(v1 Comparable, v2 Comparable) bool {
-	return v1.(*Version).LessOrEqual(v2.(*Version))
+	return compareElements(v1, v2, (*Version).LessOrEqual, (*Version).GreaterOrEqual)
 }
 
-// Equal checks if element e1 is equal to e2
+// Equal checks if element e1 is equal to e2. Elements can be versions,
+// x-range versions or their string representation
 func Equal(v1 Comparable, v2 Comparable) bool {
-	return v1.(*Version).Equal(v2.(*Version))
+	return compareElements(v1, v2, (*Version).Equal, (*Version).Equal)
 }
@@ -5,15 +5,21 @@ import "testing"
 const HonorPreRelease = true
 
 var versionComparissons = map[vPair]int{
-	np("1.3", "1.1"):                          1,
-	np("2.4.2", "2.4.2"):                      0,
-	np("4.1", "4"):                            1,
-	np("4.1.1", "4.1"):                        1,
-	np("3.1.3", "3.1.20"):                     -1,
-	np("0", "1"):                              -1,
-	np("0.1", "0.1"):                          0,
-	np("1.3.0-0", "1.3.0-1"):                  0,
-	np("1.3.0-0", "1.3.0-1", HonorPreRelease): -1,
+	np("1.3", "1.1"):                           1,
+	np("2.4.2", "2.4.2"):                       0,
+	np("4.1", "4"):                             1,
+	np("4.1.1", "4.1"):                         1,
+	np("3.1.3", "3.1.20"):                      -1,
+	np("0", "1"):                               -1,
+	np("0.1", "0.1"):                           0,
+	np("1.3.0-0", "1.3.0-1"):                   -1,
+	np("1.3.0-0", "1.3.0-1", HonorPreRelease):  -1,
+	np("1.3.0-0", "1.3.0-1", !HonorPreRelease): 0,
+	np("1.0.0-rc.10", "1.0.0-rc.2"):            1,
+	np("1.0.0-rc.1", "1.0.0"):                  -1,
+	np("1.0.0-alpha.beta", "1.0.0-alpha.1"):    1,
+	np("1.0.0-alpha", "1.0.0-alpha.1"):         -1,
+	np("1.0.0+build.1", "1.0.0+build.2"):       0,
 	//	np("1.2.4", "1.*"):    -1,
 }
 
@@ -143,3 +149,51 @@ func TestSatisfies(t *testing.T) {
 	}
 
 }
+
+func TestCompareMixedElements(t *testing.T) {
+	v := MustParseVersion("1.3.4")
+	for _, test := range []struct {
+		v1, v2         Comparable
+		greater, equal bool
+	}{
+		{v1: v, v2: "1.2.0", greater: true},
+		{v1: "1.2.0", v2: v, greater: false},
+		{v1: "1.3.4", v2: v, equal: true},
+		{v1: v, v2: MustParseGlobVersion("1.3.x"), equal: true},
+		{v1: MustParseGlobVersion("1.3.x"), v2: v, equal: true},
+		{v1: MustParseGlobVersion("2.x"), v2: v, greater: true},
+		{v1: MustParseGlobVersion("1.2.3"), v2: "1.2.3", equal: true},
+	} {
+		if Greater(test.v1, test.v2) != test.greater {
+			t.Errorf("Expected Greater(%v, %v) to be %v", test.v1, test.v2, test.greater)
+		}
+		if Equal(test.v1, test.v2) != test.equal {
+			t.Errorf("Expected Equal(%v, %v) to be %v", test.v1, test.v2, test.equal)
+		}
+		if GreaterOrEqual(test.v1, test.v2) != (test.greater || test.equal) {
+			t.Errorf("Expected GreaterOrEqual(%v, %v) to be %v", test.v1, test.v2, test.greater || test.equal)
+		}
+		if Less(test.v1, test.v2) != (!test.greater && !test.equal) {
+			t.Errorf("Expected Less(%v, %v) to be %v", test.v1, test.v2, !test.greater && !test.equal)
+		}
+		if LessOrEqual(test.v1, test.v2) != !test.greater {
+			t.Errorf("Expected LessOrEqual(%v, %v) to be %v", test.v1, test.v2, !test.greater)
+		}
+	}
+}
+
+func TestCompareUnsupportedElements(t *testing.T) {
+	v := MustParseVersion("1.3.4")
+	var nilVersion *Version
+	for _, e := range []Comparable{nil, nilVersion, 42, "foo", "1.3.4garbage", "1.3.4 foo", "1.3.4rc1", "1.x foo"} {
+		if Greater(v, e) || Less(v, e) || Equal(v, e) || Greater(e, v) || LessOrEqual(e, v) {
+			t.Errorf("Expected %v to not be comparable", e)
+		}
+	}
+	if Greater("2.0.0garbage", "1.0.0") || Equal("1.2.3 foo", "1.2.3") {
+		t.Errorf("Expected versions followed by other text to not be comparable")
+	}
+	if Less(MustParseGlobVersion("1.x"), MustParseGlobVersion("2.x")) {
+		t.Errorf("Expected two x-ranges to not be comparable")
+	}
+}
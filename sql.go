@@ -0,0 +1,390 @@
+package semver
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"strings"
+)
+
+// scanString converts a database value into a string
+func scanString(src interface{}) (string, error) {
+	switch v := src.(type) {
+	case string:
+		return v, nil
+	case []byte:
+		return string(v), nil
+	case nil:
+		return "", fmt.Errorf("cannot scan NULL, use a nullable type instead")
+	default:
+		return "", fmt.Errorf("cannot scan %T into a version or expression", src)
+	}
+}
+
+// Scan implements sql.Scanner, allowing reading a Version from a string column
+func (v *Version) Scan(src interface{}) error {
+	str, err := scanString(src)
+	if err != nil {
+		return err
+	}
+	return v.UnmarshalText([]byte(str))
+}
+
+// Value implements driver.Valuer, storing the Version as a string
+func (v *Version) Value() (driver.Value, error) {
+	return v.String(), nil
+}
+
+// SortKey returns a string whose byte order matches the SemVer 2.0 precedence of the version, so
+// a column storing it can be used to sort versions in a database (using a binary collation, such as
+// the default one in SQLite or "C" in PostgreSQL). Build metadata is not included
+func (v *Version) SortKey() string {
+	key := fmt.Sprintf("%020d.%020d.%020d", v.Major, v.Minor, v.Patch)
+	if v.PreRelease == "" {
+		// Normal versions have higher precedence than any pre-release
+		return key + "~"
+	}
+	ids := []string{}
+	for _, id := range strings.Split(v.PreRelease, ".") {
+		if isNumeric(id) {
+			// Numeric identifiers sort first, and are compared by length before their digits
+			ids = append(ids, fmt.Sprintf("0%03d%s", len(id), id))
+		} else {
+			ids = append(ids, "1"+id)
+		}
+	}
+	// "," sorts before any character allowed in identifiers
+	return key + "-" + strings.Join(ids, ",")
+}
+
+// Scan implements sql.Scanner, allowing reading an Expr from a string column
+func (e *Expr) Scan(src interface{}) error {
+	str, err := scanString(src)
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalText([]byte(str))
+}
+
+// Value implements driver.Valuer, storing the Expr as a string in its text form
+func (e *Expr) Value() (driver.Value, error) {
+	text, _ := e.MarshalText()
+	return string(text), nil
+}
+
+// NullVersion represents a Version that may be NULL in a database
+type NullVersion struct {
+	Version Version
+	// Valid is true if Version is not NULL
+	Valid bool
+}
+
+// Scan implements sql.Scanner
+func (n *NullVersion) Scan(src interface{}) error {
+	if src == nil {
+		n.Version, n.Valid = Version{}, false
+		return nil
+	}
+	if err := n.Version.Scan(src); err != nil {
+		return err
+	}
+	n.Valid = true
+	return nil
+}
+
+// Value implements driver.Valuer
+func (n NullVersion) Value() (driver.Value, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+	return n.Version.Value()
+}
+
+// NullExpr represents an Expr that may be NULL in a database
+type NullExpr struct {
+	Expr Expr
+	// Valid is true if Expr is not NULL
+	Valid bool
+}
+
+// Scan implements sql.Scanner
+func (n *NullExpr) Scan(src interface{}) error {
+	if src == nil {
+		n.Expr, n.Valid = Expr{}, false
+		return nil
+	}
+	if err := n.Expr.Scan(src); err != nil {
+		return err
+	}
+	n.Valid = true
+	return nil
+}
+
+// Value implements driver.Valuer
+func (n NullExpr) Value() (driver.Value, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+	return n.Expr.Value()
+}
+
+// SQLDialect defines the flavour of SQL generated by CompileSQL
+type SQLDialect int
+
+const (
+	// PostgreSQL uses numbered placeholders ($1, $2...)
+	PostgreSQL SQLDialect = iota
+	// SQLite uses anonymous placeholders (?)
+	SQLite
+)
+
+// SQLColumns defines the columns CompileSQL compares versions against. They are inserted verbatim
+// in the generated SQL, so they can be qualified ("d.major") but must never come from user input.
+// Major, Minor and Patch must be integer columns and PreRelease a text column storing an empty
+// string for release versions. If SortKey is set, it must be a text column storing Version.SortKey
+// and it is used instead of the rest, supporting any expression
+type SQLColumns struct {
+	Major      string
+	Minor      string
+	Patch      string
+	PreRelease string
+	SortKey    string
+}
+
+// DefaultSQLColumns contains the default names of the columns used by CompileSQL
+var DefaultSQLColumns = SQLColumns{Major: "major", Minor: "minor", Patch: "patch", PreRelease: "prerelease"}
+
+type sqlConfig struct {
+	argOffset int
+}
+
+// SQLOption defines an option to customize CompileSQL
+type SQLOption func(*sqlConfig)
+
+// SQLArgOffset makes numbered placeholders start after n, so the predicate can be
+// combined with other conditions already using n arguments
+func SQLArgOffset(n int) SQLOption {
+	return func(c *sqlConfig) {
+		c.argOffset = n
+	}
+}
+
+type sqlCompiler struct {
+	dialect SQLDialect
+	columns SQLColumns
+	args    []interface{}
+	sqlConfig
+}
+
+// arg registers a new argument, returning its placeholder
+func (c *sqlCompiler) arg(value interface{}) string {
+	c.args = append(c.args, value)
+	if c.dialect == PostgreSQL {
+		return fmt.Sprintf("$%d", c.argOffset+len(c.args))
+	}
+	return "?"
+}
+
+// tuple returns a row value comparing the release part of the version
+func (c *sqlCompiler) tuple(op string, v *Version) string {
+	return fmt.Sprintf("(%s, %s, %s) %s (%s, %s, %s)", c.columns.Major, c.columns.Minor, c.columns.Patch,
+		op, c.arg(v.Major), c.arg(v.Minor), c.arg(v.Patch))
+}
+
+func (c *sqlCompiler) isRelease() string {
+	if c.columns.SortKey != "" {
+		return c.columns.SortKey + " LIKE '%~'"
+	}
+	return c.columns.PreRelease + " = ''"
+}
+
+func (c *sqlCompiler) isPreRelease() string {
+	if c.columns.SortKey != "" {
+		return c.columns.SortKey + " NOT LIKE '%~'"
+	}
+	return c.columns.PreRelease + " <> ''"
+}
+
+// sqlVersionKind restricts the versions a condition is evaluated against
+type sqlVersionKind int
+
+const (
+	anyVersion sqlVersionKind = iota
+	releaseVersions
+	preReleaseVersions
+)
+
+// isUnboundedBelow returns true if the lower bound b does not exclude any version of kind
+func (kind sqlVersionKind) isUnboundedBelow(b bound) bool {
+	if !b.inclusive {
+		return false
+	}
+	if kind == releaseVersions && compareVersions(b.v, lowestVersion()) <= 0 {
+		return true
+	}
+	return compareVersions(b.v, lowestPreRelease()) == 0
+}
+
+// keyInterval returns the conditions on the sort key column accepting the versions of i
+func (c *sqlCompiler) keyInterval(i interval, kind sqlVersionKind) []string {
+	key := c.columns.SortKey
+	if i.isPoint() {
+		return []string{key + " = " + c.arg(i.min.v.SortKey())}
+	}
+	conds := []string{}
+	if !kind.isUnboundedBelow(i.min) {
+		op := ">"
+		if i.min.inclusive {
+			op += "="
+		}
+		conds = append(conds, key+" "+op+" "+c.arg(i.min.v.SortKey()))
+	}
+	if i.max.v != nil {
+		op := "<"
+		if i.max.inclusive {
+			op += "="
+		}
+		conds = append(conds, key+" "+op+" "+c.arg(i.max.v.SortKey()))
+	}
+	return conds
+}
+
+// tupleBound returns the condition on the major, minor, patch and pre-release columns accepting the
+// versions of kind on the side of the bound b (a lower bound if lower is true). Pre-release
+// identifiers cannot be compared in SQL, so only pre-release limits that do not depend on them
+// are supported: an x-range limit such as 1.2.0-0, the lowest pre-release of 1.2.0, or any limit
+// when the versions are releases
+func (c *sqlCompiler) tupleBound(b bound, lower bool, kind sqlVersionKind) (string, error) {
+	v := b.v
+	switch {
+	case v.PreRelease == "" && lower && !b.inclusive:
+		return c.tuple(">", v), nil
+	case v.PreRelease == "" && !lower && b.inclusive:
+		return c.tuple("<=", v), nil
+	case v.PreRelease == "" && kind == anyVersion:
+		// Pre-releases of the limit are lower than it
+		if lower {
+			return fmt.Sprintf("(%s OR (%s AND %s))", c.tuple(">", v), c.tuple("=", v), c.isRelease()), nil
+		}
+		return fmt.Sprintf("(%s OR (%s AND %s))", c.tuple("<", v), c.tuple("=", v), c.isPreRelease()), nil
+	case v.PreRelease == "" && kind == releaseVersions:
+		if lower {
+			return c.tuple(">=", v), nil
+		}
+		return c.tuple("<", v), nil
+	case v.PreRelease == "":
+		if lower {
+			return c.tuple(">", v), nil
+		}
+		return c.tuple("<=", v), nil
+	case kind == releaseVersions || (v.PreRelease == "0" && lower == b.inclusive):
+		// The limit is below any release of its major, minor and patch, and above its pre-releases
+		// if it is their lowest one
+		if lower {
+			return c.tuple(">=", v), nil
+		}
+		return c.tuple("<", v), nil
+	default:
+		return "", fmt.Errorf("pre-release limit %s requires a sort key column", v)
+	}
+}
+
+// tupleInterval returns the conditions on the major, minor, patch and pre-release columns accepting
+// the versions of kind contained in i
+func (c *sqlCompiler) tupleInterval(i interval, kind sqlVersionKind) ([]string, error) {
+	if i.isPoint() {
+		return []string{c.tuple("=", i.min.v), c.columns.PreRelease + " = " + c.arg(i.min.v.PreRelease)}, nil
+	}
+	conds := []string{}
+	for n, b := range []bound{i.min, i.max} {
+		lower := n == 0
+		if (lower && kind.isUnboundedBelow(b)) || (!lower && b.v == nil) {
+			continue
+		}
+		cond, err := c.tupleBound(b, lower, kind)
+		if err != nil {
+			return nil, err
+		}
+		conds = append(conds, cond)
+	}
+	return conds, nil
+}
+
+// alternatives returns the conditions, joined by AND, accepting the versions of kind contained in
+// each interval of s
+func (c *sqlCompiler) alternatives(s versionSet, kind sqlVersionKind) ([][]string, error) {
+	alternatives := [][]string{}
+	for _, i := range s {
+		var conds []string
+		if c.columns.SortKey != "" {
+			conds = c.keyInterval(i, kind)
+		} else {
+			var err error
+			if conds, err = c.tupleInterval(i, kind); err != nil {
+				return nil, err
+			}
+		}
+		switch {
+		case kind == releaseVersions && !(i.isPoint() && c.columns.SortKey == ""):
+			conds = append(conds, c.isRelease())
+		case kind == preReleaseVersions && !i.isPoint():
+			conds = append(conds, c.isPreRelease())
+		}
+		alternatives = append(alternatives, conds)
+	}
+	return alternatives, nil
+}
+
+// CompileSQL returns a parameterized SQL predicate, to be used in a WHERE clause, accepting the
+// versions stored in columns that match the expression e, along with its arguments. It supports
+// expressions returned by ParseExpr and ranges.
+//
+// The predicate accepts exactly the versions accepted by e.Matches, including the pre-release rules.
+// Pre-release identifiers can only be compared using a SortKey column, so pre-release limits such
+// as in ">=1.2.3-rc.1" fail to compile with the other columns, unless they are exact versions
+func CompileSQL(e Expression, dialect SQLDialect, columns SQLColumns, opts ...SQLOption) (string, []interface{}, error) {
+	var s splitSet
+	switch x := e.(type) {
+	case *Expr:
+		s = exactSetOf(x.AST(), x.includePrerelease)
+	case *Range:
+		s = exactSetOf(&RangeNode{Range: x}, false)
+	default:
+		return "", nil, fmt.Errorf("unsupported expression type: %T", e)
+	}
+	c := &sqlCompiler{dialect: dialect, columns: columns}
+	for _, opt := range opts {
+		opt(&c.sqlConfig)
+	}
+	var alternatives [][]string
+	if s.releases.equal(s.preReleases) {
+		var err error
+		if alternatives, err = c.alternatives(s.releases, anyVersion); err != nil {
+			return "", nil, err
+		}
+	} else {
+		releases, err := c.alternatives(s.releases, releaseVersions)
+		if err != nil {
+			return "", nil, err
+		}
+		preReleases, err := c.alternatives(s.preReleases, preReleaseVersions)
+		if err != nil {
+			return "", nil, err
+		}
+		alternatives = append(releases, preReleases...)
+	}
+	switch len(alternatives) {
+	case 0:
+		return "1 = 0", c.args, nil
+	case 1:
+		if len(alternatives[0]) == 0 {
+			return "1 = 1", c.args, nil
+		}
+		return strings.Join(alternatives[0], " AND "), c.args, nil
+	}
+	list := []string{}
+	for _, conds := range alternatives {
+		// Unbounded intervals are always joined with others, so they are never empty
+		list = append(list, "("+strings.Join(conds, " AND ")+")")
+	}
+	return "(" + strings.Join(list, " OR ") + ")", c.args, nil
+}
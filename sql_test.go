@@ -0,0 +1,395 @@
+package semver
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"math/rand"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+var (
+	_ sql.Scanner   = &Version{}
+	_ driver.Valuer = &Version{}
+	_ sql.Scanner   = &Expr{}
+	_ driver.Valuer = &Expr{}
+	_ sql.Scanner   = &NullVersion{}
+	_ driver.Valuer = NullVersion{}
+	_ sql.Scanner   = &NullExpr{}
+	_ driver.Valuer = NullExpr{}
+)
+
+func TestVersionScanAndValue(t *testing.T) {
+	for _, src := range []interface{}{"1.2.3-rc.1", []byte("v1.2.3-rc.1")} {
+		v := &Version{}
+		if err := v.Scan(src); err != nil || v.String() != "1.2.3-rc.1" {
+			t.Errorf("Expected %v to be scanned as 1.2.3-rc.1 but got %v (%v)", src, v, err)
+		}
+		if value, err := v.Value(); err != nil || value != "1.2.3-rc.1" {
+			t.Errorf("Expected the value of %v to be 1.2.3-rc.1 but got %v (%v)", v, value, err)
+		}
+	}
+	for _, src := range []interface{}{nil, 42, "foo"} {
+		if err := (&Version{}).Scan(src); err == nil {
+			t.Errorf("Expected scanning %v to fail", src)
+		}
+	}
+}
+
+func TestExprScanAndValue(t *testing.T) {
+	e := &Expr{}
+	if err := e.Scan([]byte("^1.2 || ^2")); err != nil || !e.Matches(MustParseVersion("2.1.0")) {
+		t.Errorf("Expected the expression to be scanned but got %v", err)
+	}
+	if value, err := e.Value(); err != nil || value != "^1.2 || ^2" {
+		t.Errorf("Expected the value of %v to be %q but got %v (%v)", e, "^1.2 || ^2", value, err)
+	}
+	if _, ok := e.Scan("^1.2 ||| 2").(*ParseError); !ok {
+		t.Errorf("Expected scanning an invalid expression to fail with a *ParseError")
+	}
+	if err := e.Scan(nil); err == nil {
+		t.Errorf("Expected scanning NULL to fail")
+	}
+}
+
+func TestNullTypes(t *testing.T) {
+	nv := NullVersion{}
+	if err := nv.Scan(nil); err != nil || nv.Valid {
+		t.Errorf("Expected NULL to be scanned as an invalid version but got %v (%v)", nv, err)
+	}
+	if value, err := nv.Value(); err != nil || value != nil {
+		t.Errorf("Expected an invalid version to be stored as NULL but got %v (%v)", value, err)
+	}
+	if err := nv.Scan("1.2.3"); err != nil || !nv.Valid || nv.Version.String() != "1.2.3" {
+		t.Errorf("Expected 1.2.3 to be scanned but got %v (%v)", nv, err)
+	}
+	if value, err := nv.Value(); err != nil || value != "1.2.3" {
+		t.Errorf("Expected the value to be 1.2.3 but got %v (%v)", value, err)
+	}
+	if err := nv.Scan(nil); err != nil || nv.Valid {
+		t.Errorf("Expected NULL to reset the version but got %v (%v)", nv, err)
+	}
+
+	ne := NullExpr{}
+	if err := ne.Scan(nil); err != nil || ne.Valid {
+		t.Errorf("Expected NULL to be scanned as an invalid expression but got %v (%v)", ne, err)
+	}
+	if value, err := ne.Value(); err != nil || value != nil {
+		t.Errorf("Expected an invalid expression to be stored as NULL but got %v (%v)", value, err)
+	}
+	if err := ne.Scan("~1.2"); err != nil || !ne.Valid || !ne.Expr.Matches(MustParseVersion("1.2.9")) {
+		t.Errorf("Expected ~1.2 to be scanned but got %v (%v)", ne, err)
+	}
+	if value, err := ne.Value(); err != nil || value != "~1.2" {
+		t.Errorf("Expected the value to be ~1.2 but got %v (%v)", value, err)
+	}
+}
+
+type bySortKey []*Version
+
+func (l bySortKey) Len() int           { return len(l) }
+func (l bySortKey) Swap(i, j int)      { l[i], l[j] = l[j], l[i] }
+func (l bySortKey) Less(i, j int) bool { return l[i].SortKey() < l[j].SortKey() }
+
+func TestSortKey(t *testing.T) {
+	list := append(append([]string{}, precedenceList...),
+		"1.0.0-a-b", "1.0.0-a.b", "1.0.0-a", "1.0.0-1.a", "1.0.0-a.1", "1.0.0-A", "1.0.0-999", "1.0.0-1000",
+		"1.0.0-99999999999999999999999", "9223372036854775807.0.0")
+	versions := shuffledVersions(list)
+	byKey := append([]*Version{}, versions...)
+	sort.Sort(bySortKey(byKey))
+	Sort(versions)
+	for i := range versions {
+		if Compare(versions[i], byKey[i]) != 0 {
+			t.Fatalf("Expected sorting by key to match precedence but got %v instead of %v", byKey, versions)
+		}
+	}
+	if MustParseVersion("1.0.0+a").SortKey() != MustParseVersion("1.0.0+b").SortKey() {
+		t.Errorf("Expected build metadata to be ignored by SortKey")
+	}
+}
+
+func TestCompileSQL(t *testing.T) {
+	for _, test := range []struct {
+		expr     Expression
+		dialect  SQLDialect
+		columns  SQLColumns
+		expected string
+		args     []interface{}
+	}{
+		{
+			expr: MustParseExpr("^2.3"), dialect: PostgreSQL, columns: DefaultSQLColumns,
+			expected: "(major, minor, patch) >= ($1, $2, $3) AND (major, minor, patch) < ($4, $5, $6) AND prerelease = ''",
+			args:     []interface{}{int64(2), int64(3), int64(0), int64(3), int64(0), int64(0)},
+		},
+		{
+			expr: MustParseExpr("1.2.3 || >=2.0.0", IncludePrerelease), dialect: SQLite, columns: DefaultSQLColumns,
+			expected: "(((major, minor, patch) = (?, ?, ?) AND prerelease = ?) OR " +
+				"(((major, minor, patch) > (?, ?, ?) OR ((major, minor, patch) = (?, ?, ?) AND prerelease = ''))))",
+			args: []interface{}{int64(1), int64(2), int64(3), "", int64(2), int64(0), int64(0), int64(2), int64(0), int64(0)},
+		},
+		{
+			// Pre-releases are compared ignoring x-ranges, so ">=2" accepts 2.0.0-rc.1
+			expr: MustParseExpr(">=2", IncludePrerelease), dialect: SQLite, columns: DefaultSQLColumns,
+			expected: "(major, minor, patch) >= (?, ?, ?)",
+			args:     []interface{}{int64(2), int64(0), int64(0)},
+		},
+		{
+			expr: MustParseRange("<1.0.0", IncludePrerelease), dialect: SQLite, columns: DefaultSQLColumns,
+			expected: "((major, minor, patch) < (?, ?, ?) OR ((major, minor, patch) = (?, ?, ?) AND prerelease <> ''))",
+			args:     []interface{}{int64(1), int64(0), int64(0), int64(1), int64(0), int64(0)},
+		},
+		{
+			expr: MustParseExpr("*"), dialect: PostgreSQL, columns: DefaultSQLColumns,
+			expected: "prerelease = ''",
+		},
+		{
+			expr: MustParseExpr("", IncludePrerelease), dialect: PostgreSQL, columns: DefaultSQLColumns,
+			expected: "1 = 1",
+		},
+		{
+			expr: MustParseExpr(">2 <1"), dialect: PostgreSQL, columns: DefaultSQLColumns,
+			expected: "1 = 0",
+		},
+		{
+			expr: MustParseExpr("=1.2.3-rc.1"), dialect: PostgreSQL, columns: SQLColumns{Major: "d.ma", Minor: "d.mi", Patch: "d.pa", PreRelease: "d.pre"},
+			expected: "(d.ma, d.mi, d.pa) = ($1, $2, $3) AND d.pre = $4",
+			args:     []interface{}{int64(1), int64(2), int64(3), "rc.1"},
+		},
+		{
+			expr: MustParseExpr(">=1.2.3-rc.1 <2"), dialect: SQLite, columns: SQLColumns{SortKey: "k"},
+			expected: "((k >= ? AND k < ? AND k LIKE '%~') OR (k >= ? AND k < ? AND k NOT LIKE '%~'))",
+			args: []interface{}{
+				MustParseVersion("1.2.3-rc.1").SortKey(), MustParseVersion("2.0.0-0").SortKey(),
+				MustParseVersion("1.2.3-rc.1").SortKey(), MustParseVersion("1.2.3").SortKey(),
+			},
+		},
+		{
+			expr: MustParseExpr("<1 || 1.5.0", IncludePrerelease), dialect: PostgreSQL, columns: SQLColumns{SortKey: "k"},
+			expected: "((k < $1) OR (k = $2))",
+			args:     []interface{}{MustParseVersion("1.0.0-0").SortKey(), MustParseVersion("1.5.0").SortKey()},
+		},
+	} {
+		sql, args, err := CompileSQL(test.expr, test.dialect, test.columns)
+		if err != nil || sql != test.expected || fmt.Sprint(args) != fmt.Sprint(test.args) {
+			t.Errorf("Expected %q to compile to %q %v but got %q %v (%v)", test.expr, test.expected, test.args, sql, args, err)
+		}
+	}
+}
+
+func TestCompileSQLOptions(t *testing.T) {
+	sql, args, err := CompileSQL(MustParseExpr("1.2.3"), PostgreSQL, DefaultSQLColumns, SQLArgOffset(2))
+	if expected := "(major, minor, patch) = ($3, $4, $5) AND prerelease = $6"; err != nil || sql != expected || len(args) != 4 {
+		t.Errorf("Expected %q but got %q %v (%v)", expected, sql, args, err)
+	}
+}
+
+func TestCompileSQLErrors(t *testing.T) {
+	if _, _, err := CompileSQL(MustParseExpr(">=1.2.3-rc.1"), SQLite, DefaultSQLColumns); err == nil {
+		t.Errorf("Expected a pre-release limit to require a sort key column")
+	}
+	if _, _, err := CompileSQL(fakeExpression{}, SQLite, DefaultSQLColumns); err == nil {
+		t.Errorf("Expected an unsupported expression to fail")
+	}
+}
+
+type fakeExpression struct{}
+
+func (fakeExpression) Matches(v *Version) bool { return true }
+func (fakeExpression) String() string          { return "fake" }
+
+// sqlEvaluator evaluates the predicates generated by CompileSQL against a single row, supporting
+// the subset of SQL they use: row values, comparisons, LIKE, AND, OR and both kinds of placeholders
+type sqlEvaluator struct {
+	tokens []string
+	pos    int
+	args   []interface{}
+	argPos int
+	row    map[string]interface{}
+}
+
+var sqlTokenRe = regexp.MustCompile(`\s*(<>|<=|>=|[=<>(),?]|\$[0-9]+|'[^']*'|[0-9]+|[A-Za-z_][A-Za-z_.]*)`)
+
+func evaluateSQL(predicate string, args []interface{}, v *Version) (bool, error) {
+	row := map[string]interface{}{"major": v.Major, "minor": v.Minor, "patch": v.Patch, "prerelease": v.PreRelease, "k": v.SortKey()}
+	e := &sqlEvaluator{args: args, row: row}
+	for rest := predicate; strings.TrimSpace(rest) != ""; {
+		m := sqlTokenRe.FindStringSubmatchIndex(rest)
+		if m == nil || m[0] != 0 {
+			return false, fmt.Errorf("unexpected SQL %q", rest)
+		}
+		e.tokens = append(e.tokens, rest[m[2]:m[3]])
+		rest = rest[m[1]:]
+	}
+	res, err := e.or()
+	if err == nil && e.pos != len(e.tokens) {
+		err = fmt.Errorf("unexpected SQL token %q", e.tokens[e.pos])
+	}
+	b, ok := res.(bool)
+	if err == nil && !ok {
+		err = fmt.Errorf("SQL predicate %q is not a condition", predicate)
+	}
+	return b, err
+}
+
+func (e *sqlEvaluator) accept(token string) bool {
+	if e.pos < len(e.tokens) && e.tokens[e.pos] == token {
+		e.pos++
+		return true
+	}
+	return false
+}
+
+func (e *sqlEvaluator) or() (interface{}, error) {
+	return e.chain("OR", e.and)
+}
+
+func (e *sqlEvaluator) and() (interface{}, error) {
+	return e.chain("AND", e.comparison)
+}
+
+// chain evaluates operands joined by the logical operator op
+func (e *sqlEvaluator) chain(op string, operand func() (interface{}, error)) (interface{}, error) {
+	res, err := operand()
+	for err == nil && e.accept(op) {
+		var next interface{}
+		if next, err = operand(); err == nil {
+			b1, ok1 := res.(bool)
+			b2, ok2 := next.(bool)
+			if !ok1 || !ok2 {
+				return nil, fmt.Errorf("%s requires conditions", op)
+			}
+			res = map[string]bool{"AND": b1 && b2, "OR": b1 || b2}[op]
+		}
+	}
+	return res, err
+}
+
+func (e *sqlEvaluator) comparison() (interface{}, error) {
+	left, err := e.operand()
+	if err != nil || e.pos == len(e.tokens) {
+		return left, err
+	}
+	op := e.tokens[e.pos]
+	negated := op == "NOT"
+	if negated {
+		e.pos++
+		op = "LIKE"
+		if !e.accept(op) {
+			return nil, fmt.Errorf("expected LIKE after NOT")
+		}
+	} else if strings.Contains("= <> < <= > >= LIKE", op) && op != "" {
+		e.pos++
+	} else {
+		return left, nil
+	}
+	right, err := e.operand()
+	if err != nil {
+		return nil, err
+	}
+	if op == "LIKE" {
+		pattern := strings.Replace(regexp.QuoteMeta(right.(string)), "%", ".*", -1)
+		return regexp.MustCompile("^"+pattern+"$").MatchString(left.(string)) != negated, nil
+	}
+	c := compareSQLValues(left, right)
+	return map[string]bool{"=": c == 0, "<>": c != 0, "<": c < 0, "<=": c <= 0, ">": c > 0, ">=": c >= 0}[op], nil
+}
+
+func (e *sqlEvaluator) operand() (interface{}, error) {
+	if e.pos == len(e.tokens) {
+		return nil, fmt.Errorf("unexpected end of SQL")
+	}
+	t := e.tokens[e.pos]
+	e.pos++
+	switch {
+	case t == "(":
+		values := []interface{}{}
+		for {
+			v, err := e.or()
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, v)
+			if !e.accept(",") {
+				break
+			}
+		}
+		if !e.accept(")") {
+			return nil, fmt.Errorf("expected )")
+		}
+		if len(values) == 1 {
+			return values[0], nil
+		}
+		return values, nil
+	case t == "?":
+		e.argPos++
+		return e.args[e.argPos-1], nil
+	case t[0] == '$':
+		n, _ := strconv.Atoi(t[1:])
+		return e.args[n-1], nil
+	case t[0] == '\'':
+		return t[1 : len(t)-1], nil
+	case t[0] >= '0' && t[0] <= '9':
+		n, _ := strconv.ParseInt(t, 10, 64)
+		return n, nil
+	}
+	if v, ok := e.row[t]; ok {
+		return v, nil
+	}
+	return nil, fmt.Errorf("unexpected SQL token %q", t)
+}
+
+// compareSQLValues compares integers, strings (as with a binary collation) and row values
+func compareSQLValues(v1, v2 interface{}) int {
+	switch x := v1.(type) {
+	case int64:
+		return compareInt64(x, v2.(int64))
+	case string:
+		return strings.Compare(x, v2.(string))
+	default:
+		row2 := v2.([]interface{})
+		for i, item := range x.([]interface{}) {
+			if c := compareSQLValues(item, row2[i]); c != 0 {
+				return c
+			}
+		}
+		return 0
+	}
+}
+
+func TestCompileSQLMatchesExpressions(t *testing.T) {
+	r := rand.New(rand.NewSource(42))
+	candidates := []*Version{}
+	for _, str := range []string{"0.0.0", "0.0.0-0", "1.2.3", "1.2.3-rc.1", "2.0.0-0", "2.3.0-rc.1", "10.100.1000"} {
+		candidates = append(candidates, MustParseVersion(str))
+	}
+	for i := 0; i < 1000; i++ {
+		candidates = append(candidates, MustParseVersion(randomVersionString(r)))
+	}
+	exprs := append([]string{"^2.3", ">=1.2.3-rc.1 >1.0.0 <2", ">=2 >=1.5.0-rc.1"}, exactSetTestExpressions...)
+	for _, str := range exprs {
+		for _, opts := range [][]ParseOption{nil, {IncludePrerelease}} {
+			e := MustParseExpr(str, opts...)
+			for _, columns := range []SQLColumns{DefaultSQLColumns, {SortKey: "k"}} {
+				predicate, args, err := CompileSQL(e, PostgreSQL, columns)
+				if err != nil {
+					if columns.SortKey != "" {
+						t.Errorf("Expected %q to compile with a sort key but got %v", str, err)
+					}
+					continue
+				}
+				for _, v := range candidates {
+					res, err := evaluateSQL(predicate, args, v)
+					if expected := e.Matches(v); err != nil || res != expected {
+						t.Errorf("Expected the SQL predicate of %q (include pre-releases: %v) to accept %v: %v but got %v (%s %v: %v)",
+							str, opts != nil, v, expected, res, predicate, args, err)
+					}
+				}
+			}
+		}
+	}
+}
@@ -7,102 +7,6 @@ import (
 	"strings"
 )
 
-func namedReEvaluate(re *regexp.Regexp, str string) (matched bool, mapping map[string]string) {
-	result := re.FindStringSubmatch(str)
-	mapping = make(map[string]string)
-
-	if len(result) == 0 {
-		return false, mapping
-	}
-
-	for i, name := range re.SubexpNames() {
-		if name == "" {
-			continue
-		}
-		// Don't overwrite already detected elements
-		if v, ok := mapping[name]; ok && v != "" {
-			continue
-		}
-		mapping[name] = result[i]
-	}
-	return true, mapping
-}
-
-func digitsRange(n int, up bool) [][]string {
-	var list [][]string
-	for _, d := range digits(n) {
-		r := []string{}
-		if up {
-			for i := d; i < 10; i++ {
-				r = append(r, strconv.Itoa(i))
-			}
-		} else {
-			for i := 0; i <= d; i++ {
-				r = append([]string{strconv.Itoa(i)}, r...)
-
-			}
-		}
-		list = append(list, r)
-	}
-	return list
-}
-
-func digits(n int) (r []int) {
-	for _, i := range strings.Split(strconv.Itoa(n), "") {
-		v, _ := strconv.Atoi(i)
-		r = append(r, v)
-	}
-	return r
-}
-
-func gt(n int) string {
-	return fmt.Sprintf(`(%s)`, strings.Join(nPatterns(n, true)[1:], `|`))
-}
-
-func lt(n int) string {
-	return fmt.Sprintf(`(%s)`, strings.Join(nPatterns(n, false)[1:], `|`))
-}
-func lte(n int) string {
-	return fmt.Sprintf(`(%s)`, strings.Join(nPatterns(n, false)[0:], `|`))
-}
-
-func gte(n int) string {
-	return fmt.Sprintf(`(%s)`, strings.Join(nPatterns(n, true)[0:], `|`))
-}
-func nPatterns(n int, up bool) []string {
-	r := digitsRange(n, up)
-	t := []string{strconv.Itoa(n)}
-
-	for i := len(r) - 1; i >= 0; i-- {
-		end := r[i]
-		str := ""
-		for j := 0; j < i; j++ {
-			if str == "" && r[j][0] == "0" {
-				continue
-			}
-			str += r[j][0]
-		}
-		captGrp := strings.Join(end[1:], `|`)
-		if captGrp != "" && (str != "" || captGrp != "0") {
-			str += `(` + captGrp + `)`
-		}
-		if extra := len(r) - i - 1; extra > 0 {
-			str += fmt.Sprintf(`\d{%d}`, extra)
-		}
-		if str != "" {
-			t = append(t, str)
-		} else {
-			t = append(t, `(0)`)
-		}
-	}
-	if up {
-		t = append(t, fmt.Sprintf(`\d{%d,}`, len(r)+1))
-	} else if len(r) > 1 {
-		t = append(t, fmt.Sprintf(`\d{,%d}`, len(r)-1))
-	}
-	return t
-}
-
 func isInt(str string) bool {
 	if _, err := strconv.Atoi(str); err != nil {
 		return false
@@ -152,6 +56,18 @@ func compareInt(i1, i2 int) int {
 	}
 }
 
+// compareInt64 is the int64 variant of compareInt, used for the version elements
+func compareInt64(i1, i2 int64) int {
+	switch {
+	case i1 < i2:
+		return -1
+	case i1 > i2:
+		return 1
+	default:
+		return 0
+	}
+}
+
 func comparePreReleases(pr1, pr2 string) (res int, err error) {
 	switch {
 	case pr1 == pr2:
@@ -186,3 +102,74 @@ func comparePreReleases(pr1, pr2 string) (res int, err error) {
 	}
 	return res, nil
 }
+
+// compareIdentifiers compares two pre-release identifiers following the SemVer 2.0 rules:
+// numeric identifiers are compared numerically and have lower precedence than alphanumeric
+// ones, which are compared lexically in ASCII sort order
+func compareIdentifiers(id1, id2 string) int {
+	num1, num2 := isNumeric(id1), isNumeric(id2)
+	switch {
+	case num1 && num2:
+		if len(id1) != len(id2) {
+			return compareInt(len(id1), len(id2))
+		}
+		return strings.Compare(id1, id2)
+	case num1:
+		return -1
+	case num2:
+		return 1
+	default:
+		return strings.Compare(id1, id2)
+	}
+}
+
+// isNumeric returns true if str is only composed of digits. Unlike isInt, it supports
+// numbers of any length
+func isNumeric(str string) bool {
+	if str == "" {
+		return false
+	}
+	for _, c := range str {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// compareSemverPreReleases compares two pre-release strings following the SemVer 2.0
+// precedence rules. An empty pre-release (a normal version) has higher precedence than any other
+func compareSemverPreReleases(pr1, pr2 string) int {
+	switch {
+	case pr1 == pr2:
+		return 0
+	case pr1 == "":
+		return 1
+	case pr2 == "":
+		return -1
+	}
+	ids1 := strings.Split(pr1, ".")
+	ids2 := strings.Split(pr2, ".")
+	for i := 0; i < len(ids1) && i < len(ids2); i++ {
+		if res := compareIdentifiers(ids1[i], ids2[i]); res != 0 {
+			return res
+		}
+	}
+	return compareInt(len(ids1), len(ids2))
+}
+
+// trimDigits removes the leading zeros of a number of any length
+func trimDigits(digits string) string {
+	if digits = strings.TrimLeft(digits, "0"); digits == "" {
+		return "0"
+	}
+	return digits
+}
+
+// compareDigits compares two numbers of any length, given as digits without leading zeros
+func compareDigits(d1, d2 string) int {
+	if len(d1) != len(d2) {
+		return compareInt(len(d1), len(d2))
+	}
+	return strings.Compare(d1, d2)
+}
@@ -67,7 +67,7 @@ type Version struct {
 	majorPresent         bool
 	minorPresent         bool
 	patchPresent         bool
-	comparePreReleases   bool
+	ignorePreReleases    bool
 	preReleaseComparator func(pr1, pr2 string) (int, error)
 }
 
@@ -75,11 +75,11 @@ type Version struct {
 func (v *Version) MarshalJSON() (data []byte, err error) {
 	return json.Marshal(v.String())
 }
+
+// HonorPreRelease configures whether pre-releases are taken into account when comparing v
+// with other versions. They are honored by default, following the SemVer 2.0 precedence rules
 func (v *Version) HonorPreRelease(value bool) {
-	v.comparePreReleases = value
-	if value && v.preReleaseComparator == nil {
-		v.preReleaseComparator = comparePreReleases
-	}
+	v.ignorePreReleases = !value
 }
 
 // Hack allows making a semver deviate from the standard semver behavior in different ways
@@ -117,8 +117,7 @@ func NewVersion(major int64, minor int64, patch int64, extra ...string) *Version
 	}
 	return &Version{
 		Major: major, Minor: minor, Patch: patch,
-		preReleaseComparator: comparePreReleases,
-		majorPresent:         true, minorPresent: true, patchPresent: true,
+		majorPresent: true, minorPresent: true, patchPresent: true,
 		PreRelease: preRelease, Build: build,
 	}
 }
@@ -149,11 +148,14 @@ func (v *Version) compare(v2 *Version) int {
 			return -1
 		}
 	}
-	if v.comparePreReleases && v.preReleaseComparator != nil {
+	if v.ignorePreReleases {
+		return 0
+	}
+	if v.preReleaseComparator != nil {
 		res, _ := v.preReleaseComparator(v.PreRelease, v2.PreRelease)
 		return res
 	}
-	return 0
+	return compareSemverPreReleases(v.PreRelease, v2.PreRelease)
 }
 
 func (v *Version) equal(v2 *Version) bool {
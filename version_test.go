@@ -38,3 +38,32 @@ func TestParseVersion(t *testing.T) {
 		}
 	}
 }
+
+func TestPreReleaseHacks(t *testing.T) {
+	for _, test := range []struct {
+		v1, v2   string
+		hack     Hack
+		expected int
+	}{
+		{v1: "1.0.0-rc.10", v2: "1.0.0-rc.2", expected: 1},
+		{v1: "1.0.0-rc.10", v2: "1.0.0-rc.2", hack: HeuristicPreReleases, expected: 1},
+		{v1: "1.0.0-pre-alpha", v2: "1.0.0-alpha", expected: 1},
+		{v1: "1.0.0-pre-alpha", v2: "1.0.0-alpha", hack: HeuristicPreReleases, expected: -1},
+		{v1: "1.0.0-beta2", v2: "1.0.0-alpha10", expected: 1},
+		{v1: "1.0.0-beta2", v2: "1.0.0-beta10", expected: 1},
+		{v1: "1.0.0-beta2", v2: "1.0.0-beta10", hack: HeuristicPreReleases, expected: -1},
+		{v1: "1.0.0-final", v2: "1.0.0-rc", hack: HeuristicPreReleases, expected: 1},
+		{v1: "1.0.0-final", v2: "1.0.0-rc", expected: -1},
+		{v1: "1.0.0-5", v2: "1.0.0-rc", hack: SupportRevisionsInPreRelease, expected: 1},
+		{v1: "1.0.0-5", v2: "1.0.0-rc", expected: -1},
+	} {
+		v1 := MustParseVersion(test.v1)
+		if test.hack != nil {
+			v1 = v1.Hack(test.hack)
+		}
+		v2 := MustParseVersion(test.v2)
+		if res := v1.compare(v2); res != test.expected {
+			t.Errorf("Expected comparing %q with %q to return %d but got %d", test.v1, test.v2, test.expected, res)
+		}
+	}
+}